@@ -0,0 +1,208 @@
+package reversi
+
+// Analysis holds the features of a position that players look at when they
+// review a game.
+type Analysis struct {
+	// Players holds the features of each player in the order of their tokens.
+	Players []PlayerAnalysis `json:"players"`
+	// Regions lists the empty regions, groups of empty cells connected
+	// across sides or corners.
+	Regions [][]Point `json:"regions"`
+	// OddRegions is the number of regions with an odd number of cells. The
+	// player who moves last in a region usually gains from it.
+	OddRegions int `json:"oddRegions"`
+}
+
+// PlayerAnalysis holds the features of one player.
+type PlayerAnalysis struct {
+	Token int `json:"token"`
+	// Mobility is the number of legal moves.
+	Mobility int `json:"mobility"`
+	// PotentialMobility is the number of empty cells next to an opponent
+	// disc, where moves may open up later.
+	PotentialMobility int `json:"potentialMobility"`
+	// Frontier is the number of discs next to an empty cell.
+	Frontier int `json:"frontier"`
+	// Stable lists the discs that can no longer be flipped.
+	Stable []Point `json:"stable"`
+	// Corners and Edges are the number of corners and other edge cells
+	// owned.
+	Corners int `json:"corners"`
+	Edges   int `json:"edges"`
+}
+
+// neighbours are the offsets of the eight cells around a cell.
+var neighbours = [8]Point{
+	{-1, -1}, {0, -1}, {1, -1},
+	{-1, 0}, {1, 0},
+	{-1, 1}, {0, 1}, {1, 1},
+}
+
+// Analyze computes the Analysis of the current position.
+func (g GameBoard) Analyze() Analysis {
+	a := Analysis{Players: make([]PlayerAnalysis, len(g.players))}
+	for i, p := range g.players {
+		a.Players[i] = PlayerAnalysis{Token: i + 1, Mobility: len(p.possibleMoves)}
+	}
+
+	n := g.cfg.size
+	for y, row := range g.board {
+		for x, cell := range row {
+			p := Point{x, y}
+			switch {
+			case cell == 0:
+				// Each player with a disc around p could play there one day.
+				seen := make(map[int]bool)
+				for _, d := range neighbours {
+					if c := g.Cell(Point{x + d.X, y + d.Y}); c > 0 {
+						seen[c] = true
+					}
+				}
+				for i := range a.Players {
+					for token := range seen {
+						if token != i+1 {
+							a.Players[i].PotentialMobility++
+							break
+						}
+					}
+				}
+			case cell > 0:
+				pa := &a.Players[cell-1]
+				if g.nextToEmpty(p) {
+					pa.Frontier++
+				}
+				onX, onY := x == 0 || x == n-1, y == 0 || y == n-1
+				if onX && onY {
+					pa.Corners++
+				} else if onX || onY {
+					pa.Edges++
+				}
+			}
+		}
+	}
+
+	for _, p := range g.stableDiscs() {
+		pa := &a.Players[g.board[p.Y][p.X]-1]
+		pa.Stable = append(pa.Stable, p)
+	}
+	a.Regions = g.emptyRegions()
+	for _, r := range a.Regions {
+		if len(r)%2 == 1 {
+			a.OddRegions++
+		}
+	}
+	return a
+}
+
+// nextToEmpty reports whether any cell around p is empty.
+func (g GameBoard) nextToEmpty(p Point) bool {
+	for _, d := range neighbours {
+		q := Point{p.X + d.X, p.Y + d.Y}
+		if g.onBoard(q) && g.board[q.Y][q.X] == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (g GameBoard) onBoard(p Point) bool {
+	return p.X >= 0 && p.X < g.cfg.size && p.Y >= 0 && p.Y < g.cfg.size
+}
+
+// stableDiscs returns the discs that cannot be flipped for the rest of the
+// game, in row-major order. A disc is flipped along one of the four lines
+// through it, which cannot happen when the line is full, or when a side of
+// the line ends at the disc or goes on with a stable disc of the same owner.
+// Starting from none, discs are marked stable until nothing changes. Some
+// stable discs may be missed, but no disc is marked wrongly.
+func (g GameBoard) stableDiscs() []Point {
+	n := g.cfg.size
+	stable := make([][]bool, n)
+	for y := range stable {
+		stable[y] = make([]bool, n)
+	}
+	// secure reports whether the side of p towards d cannot take part in a
+	// flip of p.
+	secure := func(p, d Point) bool {
+		q := Point{p.X + d.X, p.Y + d.Y}
+		return !g.onBoard(q) || g.board[q.Y][q.X] == Blocked ||
+			g.board[q.Y][q.X] == g.board[p.Y][p.X] && stable[q.Y][q.X]
+	}
+	// full reports whether the line through p along d has no empty cell up
+	// to the edges or blocked cells.
+	full := func(p, d Point) bool {
+		for _, s := range []int{1, -1} {
+			for q := (Point{p.X + s*d.X, p.Y + s*d.Y}); g.onBoard(q) && g.board[q.Y][q.X] != Blocked; q = (Point{q.X + s*d.X, q.Y + s*d.Y}) {
+				if g.board[q.Y][q.X] == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	lines := [4]Point{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+
+	for changed := true; changed; {
+		changed = false
+		for y, row := range g.board {
+			for x, cell := range row {
+				p := Point{x, y}
+				if cell <= 0 || stable[y][x] {
+					continue
+				}
+				ok := true
+				for _, d := range lines {
+					if !secure(p, d) && !secure(p, Point{-d.X, -d.Y}) && !full(p, d) {
+						ok = false
+						break
+					}
+				}
+				if ok {
+					stable[y][x], changed = true, true
+				}
+			}
+		}
+	}
+
+	var res []Point
+	for y, row := range stable {
+		for x, s := range row {
+			if s {
+				res = append(res, Point{x, y})
+			}
+		}
+	}
+	return res
+}
+
+// emptyRegions groups the empty cells into regions connected across sides
+// or corners. Each region is in the order it was flooded from its first cell
+// in row-major order.
+func (g GameBoard) emptyRegions() [][]Point {
+	n := g.cfg.size
+	seen := make([][]bool, n)
+	for y := range seen {
+		seen[y] = make([]bool, n)
+	}
+	var regions [][]Point
+	for y, row := range g.board {
+		for x, cell := range row {
+			if cell != 0 || seen[y][x] {
+				continue
+			}
+			seen[y][x] = true
+			region := []Point{{x, y}}
+			for i := 0; i < len(region); i++ {
+				for _, d := range neighbours {
+					q := Point{region[i].X + d.X, region[i].Y + d.Y}
+					if g.onBoard(q) && g.board[q.Y][q.X] == 0 && !seen[q.Y][q.X] {
+						seen[q.Y][q.X] = true
+						region = append(region, q)
+					}
+				}
+			}
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
@@ -0,0 +1,96 @@
+package reversi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeStart(t *testing.T) {
+	a := NewGameBoard(*NewPlayer(1), *NewPlayer(2)).Analyze()
+	for _, pa := range a.Players {
+		want := PlayerAnalysis{Token: pa.Token, Mobility: 4, PotentialMobility: 10, Frontier: 2}
+		if !reflect.DeepEqual(pa, want) {
+			t.Errorf("Analyze() of player %v, want: %+v, got %+v", pa.Token, want, pa)
+		}
+	}
+	if len(a.Regions) != 1 || len(a.Regions[0]) != 60 || a.OddRegions != 0 {
+		t.Errorf("Analyze() regions, want one of 60 cells, got %v regions, %v odd", len(a.Regions), a.OddRegions)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	g := mustParsePosition(t, `
+		XXXO----
+		XX-O----
+		X--O----
+		--------
+		---XO---
+		---OX---
+		------XX
+		-----OOO
+		X`)
+	a := g.Analyze()
+
+	if got, want := a.Players[0].Stable, []Point{{0, 0}, {1, 0}, {2, 0}, {0, 1}, {1, 1}, {0, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze() stable discs of X, want: %v, got %v", want, got)
+	}
+	if got, want := a.Players[1].Stable, []Point{{7, 7}, {6, 7}, {5, 7}}; !equalMapUnorderedSlice(map[Point][]Point{{}: got}, map[Point][]Point{{}: want}) {
+		t.Errorf("Analyze() stable discs of O, want: %v, got %v", want, got)
+	}
+	if got, want := []int{a.Players[0].Corners, a.Players[0].Edges, a.Players[1].Corners, a.Players[1].Edges}, []int{1, 5, 1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze() corners and edges, want: %v, got %v", want, got)
+	}
+	if got, want := a.Players[0].Mobility, len(g.P1().PossibleMoves()); got != want {
+		t.Errorf("Analyze() mobility, want: %v, got %v", want, got)
+	}
+}
+
+func TestAnalyzeRegions(t *testing.T) {
+	g := mustParsePosition(t, `
+		-X----
+		XX####
+		######
+		######
+		####OO
+		----O-
+		X`)
+	a := g.Analyze()
+	var sizes []int
+	for _, r := range a.Regions {
+		sizes = append(sizes, len(r))
+	}
+	if got, want := sizes, []int{1, 4, 4, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze() region sizes, want: %v, got %v", want, got)
+	}
+	if got, want := a.OddRegions, 2; got != want {
+		t.Errorf("Analyze() odd regions, want: %v, got %v", want, got)
+	}
+}
+
+// TestStableDiscsNeverFlip checks that discs found stable keep their owner
+// for the rest of random games.
+func TestStableDiscsNeverFlip(t *testing.T) {
+	boards := map[string][]GameCfgFunc{
+		"8x8":       nil,
+		"obstacles": {WithBlocked(Point{2, 2}, Point{5, 5}, Point{0, 4})},
+		"3 players": {WithSize(10), morePlayers(3)},
+	}
+	for name, cfgFuncs := range boards {
+		for seed := int64(0); seed < 5; seed++ {
+			stable := make(map[Point]int)
+			playRandomGameWith(seed, func(g *GameBoard) {
+				for p, token := range stable {
+					if g.Cell(p) != token {
+						t.Fatalf("%v seed %v turn %v: stable disc %v flipped", name, seed, g.Turn(), p)
+					}
+				}
+				for _, p := range g.stableDiscs() {
+					stable[p] = g.Cell(p)
+				}
+			}, cfgFuncs...)
+			if len(stable) == 0 {
+				t.Errorf("%v seed %v: no stable discs by the end of the game", name, seed)
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package reversi
+
+import "math/bits"
+
+const (
+	notAFile uint64 = 0xfefefefefefefefe // every cell except x == 0
+	notHFile uint64 = 0x7f7f7f7f7f7f7f7f // every cell except x == Width-1
+)
+
+// direction is a step on the bitboard. Cells are stored as bit y*Width+x, so
+// moving one column is a shift by 1 and moving one row a shift by Width. The
+// mask drops the bits that wrapped around to the other side of the board.
+type direction struct {
+	shift int
+	mask  uint64
+}
+
+var directions = [8]direction{
+	{1, notAFile}, {-1, notHFile}, // right, left
+	{Width, ^uint64(0)}, {-Width, ^uint64(0)}, // down, up
+	{Width + 1, notAFile}, {Width - 1, notHFile}, // down-right, down-left
+	{-Width + 1, notAFile}, {-Width - 1, notHFile}, // up-right, up-left
+}
+
+func (d direction) apply(b uint64) uint64 {
+	if d.shift > 0 {
+		return (b << d.shift) & d.mask
+	}
+	return (b >> -d.shift) & d.mask
+}
+
+// bitboard holds the discs of both tokens, one bit per cell. discs[0] belongs
+// to token 1 and discs[1] to token 2.
+type bitboard struct {
+	discs [2]uint64
+}
+
+func newBitboard(board [][]int) bitboard {
+	var b bitboard
+	for y, row := range board {
+		for x, cell := range row {
+			if cell == 1 || cell == 2 {
+				b.discs[cell-1] |= squareBit(Point{x, y})
+			}
+		}
+	}
+	return b
+}
+
+func (b bitboard) own(token int) uint64 {
+	return b.discs[token-1]
+}
+
+func (b bitboard) oppo(token int) uint64 {
+	return b.discs[2-token]
+}
+
+// moves returns the cells token can play on.
+func (b bitboard) moves(token int) uint64 {
+	return legalMoves(b.own(token), b.oppo(token))
+}
+
+func (b bitboard) count(token int) int {
+	return bits.OnesCount64(b.own(token))
+}
+
+// legalMoves returns the empty cells from which own brackets at least one
+// line of opp discs.
+func legalMoves(own, opp uint64) uint64 {
+	empty := ^(own | opp)
+	var moves uint64
+	for _, d := range directions {
+		x := d.apply(own) & opp
+		// A line holds at most 6 opponent discs.
+		for i := 0; i < 5; i++ {
+			x |= d.apply(x) & opp
+		}
+		moves |= d.apply(x) & empty
+	}
+	return moves
+}
+
+// flips returns the opp discs captured when own plays on sq.
+func flips(own, opp uint64, sq int) uint64 {
+	var res uint64
+	for _, d := range directions {
+		var line uint64
+		m := d.apply(1 << sq)
+		for m&opp != 0 {
+			line |= m
+			m = d.apply(m)
+		}
+		if m&own != 0 {
+			res |= line
+		}
+	}
+	return res
+}
+
+func squareBit(p Point) uint64 {
+	return 1 << (p.Y*Width + p.X)
+}
+
+func squarePoint(sq int) Point {
+	return Point{sq % Width, sq / Width}
+}
+
+// maskPoints lists the cells set in mask in row-major order.
+func maskPoints(mask uint64) []Point {
+	res := make([]Point, 0, bits.OnesCount64(mask))
+	for ; mask != 0; mask &= mask - 1 {
+		res = append(res, squarePoint(bits.TrailingZeros64(mask)))
+	}
+	return res
+}
@@ -0,0 +1,116 @@
+package reversi
+
+import (
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// playRandomGame plays a seeded random game and calls visit before every move.
+func playRandomGame(seed int64, visit func(g *GameBoard)) {
+	playRandomGameWith(seed, visit)
+}
+
+// playRandomGameWith is playRandomGame on the board set up by cfgFuncs.
+func playRandomGameWith(seed int64, visit func(g *GameBoard), cfgFuncs ...GameCfgFunc) {
+	rng := rand.New(rand.NewSource(seed))
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), cfgFuncs...)
+	for !g.EndGame() {
+		visit(g)
+		p := g.CurrentPlayer()
+		moves := p.PossibleMoves()
+		if len(moves) == 0 {
+			g.Pass()
+			continue
+		}
+		g.Mark(moves[rng.Intn(len(moves))], *p)
+	}
+	visit(g)
+}
+
+func TestBitboardMatchesScan(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		playRandomGame(seed, func(g *GameBoard) {
+			if got, want := newBitboard(g.board), g.bb; got != want {
+				t.Fatalf("seed %v turn %v: board view out of sync, want: %v, got %v", seed, g.turn, want, got)
+			}
+			for token := 1; token <= 2; token++ {
+				if got, want := g.PossibleMoves(token), g.scanPossibleMoves(token); !equalMapUnorderedSlice(got, want) {
+					t.Fatalf("seed %v turn %v: PossibleMoves(%v), want: %v, got %v", seed, g.turn, token, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMark(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	flipped, err := g.Mark(Point{4, 2}, *g.P1())
+	if err != nil || flipped != 2 {
+		t.Fatalf("Mark(e3), want: (2, nil), got (%v, %v)", flipped, err)
+	}
+	if got, want := g.Cell(Point{4, 3}), 1; got != want {
+		t.Errorf("Cell(e4), want: %v, got %v", want, got)
+	}
+	if got, want := [2]int{g.P1().score, g.P2().score}, [2]int{4, 1}; got != want {
+		t.Errorf("scores, want: %v, got %v", want, got)
+	}
+	if _, err := g.Mark(Point{0, 0}, *g.P2()); err == nil {
+		t.Errorf("Mark(a1), want error, got nil")
+	}
+}
+
+// benchmarkPositions collects the positions of a few random games.
+func benchmarkPositions() []GameBoard {
+	var res []GameBoard
+	for seed := int64(0); seed < 4; seed++ {
+		playRandomGame(seed, func(g *GameBoard) {
+			c := *g
+			c.board = g.Board()
+			res = append(res, c)
+		})
+	}
+	return res
+}
+
+func BenchmarkPossibleMoves(b *testing.B) {
+	positions := benchmarkPositions()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := positions[i%len(positions)]
+		g.PossibleMoves(1)
+		g.PossibleMoves(2)
+	}
+}
+
+func BenchmarkScanPossibleMoves(b *testing.B) {
+	positions := benchmarkPositions()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := positions[i%len(positions)]
+		g.scanPossibleMoves(1)
+		g.scanPossibleMoves(2)
+	}
+}
+
+func BenchmarkLegalMoves(b *testing.B) {
+	positions := benchmarkPositions()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := positions[i%len(positions)]
+		g.bb.moves(1)
+		g.bb.moves(2)
+	}
+}
+
+func BenchmarkFlips(b *testing.B) {
+	positions := benchmarkPositions()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := positions[i%len(positions)]
+		own, opp := g.bb.own(1), g.bb.oppo(1)
+		for moves := legalMoves(own, opp); moves != 0; moves &= moves - 1 {
+			flips(own, opp, bits.TrailingZeros64(moves))
+		}
+	}
+}
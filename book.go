@@ -0,0 +1,146 @@
+package reversi
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Book is an opening book: positions known from move sequences together with
+// their evaluation. Positions are stored in canonical form, so a book line is
+// found whichever of the 8 symmetries of the board the game is played in.
+type Book struct {
+	// scores holds the score of each canonical position for the side to
+	// move.
+	scores map[Position]int
+}
+
+// LoadBook reads a book in text form. Each line holds a move sequence from
+// the starting position and the final disc differential for the player who
+// moved first, e.g.
+//
+//	f5d6c3d3c4f4 -2
+//
+// Blank lines and lines starting with # are skipped. The score of a position
+// in the middle of a line is the best score reachable from it in the book.
+func LoadBook(r io.Reader) (*Book, error) {
+	leaves := make(map[Position]int)
+	prefixes := map[Position]bool{startPosition().Canonical(): true}
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: want moves and score", lineNo)
+		}
+		score, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid score %q", lineNo, fields[1])
+		}
+		moves, err := ParseMoves(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		positions, err := replayMoves(moves)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if len(positions) == 0 {
+			return nil, fmt.Errorf("line %d: empty move sequence", lineNo)
+		}
+
+		for _, p := range positions {
+			prefixes[p.Canonical()] = true
+		}
+		last := positions[len(positions)-1]
+		// The first player is to move after an even number of discs were
+		// added, unless someone passed, which does not happen in openings.
+		if (Width*Height-4-last.Empties())%2 == 1 {
+			score = -score
+		}
+		leaves[last.Canonical()] = score
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	b := &Book{scores: make(map[Position]int)}
+	b.propagate(startPosition(), prefixes, leaves)
+	return b, nil
+}
+
+// LoadBookFile reads a book in text form from the named file.
+func LoadBookFile(name string) (*Book, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadBook(f)
+}
+
+// propagate computes the negamax score of p over the book lines going
+// through it. It reports false if no line goes through p.
+func (b *Book) propagate(p Position, prefixes map[Position]bool, leaves map[Position]int) (int, bool) {
+	c := p.Canonical()
+	if score, ok := b.scores[c]; ok {
+		return score, true
+	}
+	if !prefixes[c] {
+		return 0, false
+	}
+
+	best, found := 0, false
+	for moves := p.Moves(); moves != 0; moves &= moves - 1 {
+		score, ok := b.propagate(p.play(bits.TrailingZeros64(moves)), prefixes, leaves)
+		if ok && (!found || -score > best) {
+			best, found = -score, true
+		}
+	}
+	if !found {
+		best = leaves[c]
+	}
+	b.scores[c] = best
+	return best, true
+}
+
+// Len returns the number of positions in the book.
+func (b *Book) Len() int {
+	return len(b.scores)
+}
+
+// Lookup returns the book move of the side to move in p with the score it
+// leads to. It reports false when no legal move stays in the book.
+func (b *Book) Lookup(p Position) (Point, int, bool) {
+	var best Point
+	bestScore, found := 0, false
+	for moves := p.Moves(); moves != 0; moves &= moves - 1 {
+		sq := bits.TrailingZeros64(moves)
+		score, ok := b.scores[p.play(sq).Canonical()]
+		if !ok {
+			continue
+		}
+		if !found || -score > bestScore {
+			best, bestScore, found = squarePoint(sq), -score, true
+		}
+	}
+	return best, bestScore, found
+}
+
+// BestMove returns the book move in p, so a Book can be used as an Engine.
+func (b *Book) BestMove(p Position) (Point, error) {
+	move, _, ok := b.Lookup(p)
+	if !ok {
+		return Point{}, errors.New("position not in book")
+	}
+	return move, nil
+}
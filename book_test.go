@@ -0,0 +1,197 @@
+package reversi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSymmetry(t *testing.T) {
+	b := squareBit(Point{1, 0}) | squareBit(Point{2, 5})
+	tests := map[string]struct {
+		got, want uint64
+	}{
+		"mirror horizontal": {mirrorHorizontal(b), squareBit(Point{6, 0}) | squareBit(Point{5, 5})},
+		"flip vertical":     {flipVertical(b), squareBit(Point{1, 7}) | squareBit(Point{2, 2})},
+		"transpose":         {transpose(b), squareBit(Point{0, 1}) | squareBit(Point{5, 2})},
+	}
+	for name, test := range tests {
+		if test.got != test.want {
+			t.Errorf("%v, want: %x, got %x", name, test.want, test.got)
+		}
+	}
+
+	seen := make(map[uint64]bool)
+	for i := 0; i < 8; i++ {
+		seen[symmetry(b, i)] = true
+	}
+	if len(seen) != 8 {
+		t.Errorf("symmetry(), want 8 distinct images, got %v", len(seen))
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	p := randomPosition(11, 40)
+	for i := 0; i < 8; i++ {
+		q := Position{Own: symmetry(p.Own, i), Opp: symmetry(p.Opp, i)}
+		if q.Canonical() != p.Canonical() {
+			t.Errorf("Canonical() of symmetry %v differs", i)
+		}
+	}
+}
+
+func TestParseMoves(t *testing.T) {
+	got, err := ParseMoves("F5 d6,c3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Point{{5, 4}, {3, 5}, {2, 2}}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("ParseMoves(), want: %v, got %v", want, got)
+	}
+	for _, s := range []string{"f5d", "z9", "f5i1"} {
+		if _, err := ParseMoves(s); err == nil {
+			t.Errorf("ParseMoves(%q), want error, got nil", s)
+		}
+	}
+}
+
+func TestReplayMovesOrientation(t *testing.T) {
+	standard, err := ParseMoves("f5d6c3d3c4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mirrored, err := ParseMoves("c5e6f3e3f4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := replayMoves(standard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := replayMoves(mirrored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a[len(a)-1] != b[len(b)-1] {
+		t.Errorf("replayMoves() of the standard and mirrored Tiger differ")
+	}
+	if _, err := replayMoves([]Point{{0, 0}}); err == nil {
+		t.Errorf("replayMoves(a1), want error, got nil")
+	}
+}
+
+func TestBook(t *testing.T) {
+	book, err := LoadBook(strings.NewReader(`
+# two answers to the perpendicular opening and the diagonal opening
+f5d6c3 -4
+f5d6c5 +2
+f5f6 -6
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The game starts in the mirror image of the book, with c5 instead of f5.
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	first, score, ok := book.Lookup(g.Position(1))
+	if !ok || score != -6 {
+		t.Fatalf("Lookup(start), want score -6, got (%v, %v, %v)", first, score, ok)
+	}
+	p := g.Position(1).Play(first)
+	reply, score, ok := book.Lookup(p)
+	if !ok || score != 6 {
+		t.Fatalf("Lookup(after %v), want score 6, got (%v, %v, %v)", first, reply, score, ok)
+	}
+	if _, _, ok := book.Lookup(p.Play(reply)); ok {
+		t.Errorf("Lookup() after the end of the book, want false")
+	}
+
+	moves, _ := ParseMoves("f5d6")
+	positions, _ := replayMoves(moves)
+	third, score, ok := book.Lookup(positions[1])
+	if !ok || score != 2 {
+		t.Fatalf("Lookup(f5d6), want score 2, got (%v, %v, %v)", third, score, ok)
+	}
+	if name, _ := OpeningName(positions[1].Play(third)); name != "Cow" {
+		t.Errorf("OpeningName(), want: Cow, got %q", name)
+	}
+
+	if _, err := LoadBook(strings.NewReader("f5a1 0")); err == nil {
+		t.Errorf("LoadBook(illegal move), want error, got nil")
+	}
+}
+
+func TestOpeningName(t *testing.T) {
+	moves, _ := ParseMoves("f5d6c3d3c4")
+	positions, err := replayMoves(moves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range positions {
+		name, ok := OpeningName(p)
+		switch i {
+		case 1:
+			if name != "Perpendicular Opening" {
+				t.Errorf("OpeningName(f5d6), want: Perpendicular Opening, got %q", name)
+			}
+		case 4:
+			if name != "Tiger" {
+				t.Errorf("OpeningName(f5d6c3d3c4), want: Tiger, got %q", name)
+			}
+		default:
+			if ok {
+				t.Errorf("OpeningName(move %v), want none, got %q", i+1, name)
+			}
+		}
+	}
+}
+
+func TestBookPlayer(t *testing.T) {
+	book, err := LoadBook(strings.NewReader("f5f6e6f4 +3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPlayer(1, WithPlayerType(Computer), WithLevel(1), WithOpeningBook(book, 4))
+	g := NewGameBoard(*p, *NewPlayer(2))
+	move, err := g.P1().ChooseMove(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _, _ := book.Lookup(g.Position(1)); move != want {
+		t.Errorf("ChooseMove(), want book move %v, got %v", want, move)
+	}
+}
+
+func TestBookPlayerSetup(t *testing.T) {
+	start := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	moved := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	moved.Mark(moved.P1().PossibleMoves()[0], *moved.P1())
+	parsed, err := ParsePosition(start.String(), *NewPlayer(1), *NewPlayer(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	midgame, err := ParsePosition(moved.String(), *NewPlayer(1), *NewPlayer(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		g    *GameBoard
+		want bool
+	}{
+		{"start", start, true},
+		{"moves played", moved, true},
+		{"start position string", parsed, true},
+		{"handicap", NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithHandicap(2, 2)), false},
+		{"layout", NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithLayout(moved.Board())), false},
+		{"position string", midgame, false},
+	}
+	for _, tt := range tests {
+		if got := tt.g.fromStart(); got != tt.want {
+			t.Errorf("%v: fromStart(), want: %v, got %v", tt.name, tt.want, got)
+		}
+	}
+	if got := moved.playedMoves(); got != 1 {
+		t.Errorf("playedMoves(), want: 1, got %v", got)
+	}
+
+}
@@ -0,0 +1,275 @@
+// Package bot is a reference client for programs playing on a reversi server
+// through the bot API. A bot connects to /ws with bot=true, is listed in the
+// lobby, answers the challenges of other clients and plays its moves with
+// MAKE_MOVE whenever a game state says it is to move. The messages are those
+// of package protocol.
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/gorilla/websocket"
+	"github.com/oscarhkli/reversi"
+	"github.com/oscarhkli/reversi/protocol"
+)
+
+// Player decides the moves of a bot.
+type Player interface {
+	// Move returns the move to play in state, where the bot with ID id is
+	// to move.
+	Move(id string, state protocol.GameStatePayload) (reversi.Point, error)
+}
+
+// Client is a bot connected to a server.
+type Client struct {
+	conn *websocket.Conn
+	cfg  ClientCfg
+	id   string
+	name string
+	bots []protocol.BotPayload
+}
+
+type ClientCfg struct {
+	accept func(c protocol.ChallengePayload) (bool, string)
+	logf   func(format string, args ...any)
+}
+
+type ClientCfgFunc func(cfg *ClientCfg)
+
+// WithAccept decides which challenges the bot takes. It returns whether to
+// accept c, and the reason to give otherwise. Every challenge is accepted by
+// default.
+func WithAccept(accept func(c protocol.ChallengePayload) (bool, string)) ClientCfgFunc {
+	return func(cfg *ClientCfg) {
+		cfg.accept = accept
+	}
+}
+
+// WithLogf reports what the bot does through logf, log.Printf for instance.
+func WithLogf(logf func(format string, args ...any)) ClientCfgFunc {
+	return func(cfg *ClientCfg) {
+		cfg.logf = logf
+	}
+}
+
+// Dial connects to the WebSocket endpoint of a server, such as
+// "ws://localhost:8080/ws", as a bot named name.
+func Dial(ctx context.Context, endpoint, name string, cfgFuncs ...ClientCfgFunc) (*Client, error) {
+	cfg := ClientCfg{
+		accept: func(protocol.ChallengePayload) (bool, string) { return true, "" },
+		logf:   func(string, ...any) {},
+	}
+	for _, cfgFunc := range cfgFuncs {
+		cfgFunc(&cfg)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("name", name)
+	q.Set("bot", "true")
+	u.RawQuery = q.Encode()
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{conn: conn, cfg: cfg}
+	m, err := c.read()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if m.Action != protocol.RegisterResponse {
+		conn.Close()
+		return nil, fmt.Errorf("expected %v, got %v", protocol.RegisterResponse, m.Action)
+	}
+	register, err := payload[protocol.RegisterResponsePayload](m)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	c.id, c.name, c.bots = register.ID, register.Name, register.Bots
+	return c, nil
+}
+
+// ID returns the ID the server gave the bot.
+func (c *Client) ID() string {
+	return c.id
+}
+
+// Name returns the name of the bot.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Bots returns the other bots connected when the bot joined.
+func (c *Client) Bots() []protocol.BotPayload {
+	return c.bots
+}
+
+// Close disconnects the bot.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Run plays the games of the bot with p until ctx is done or the connection
+// is lost. After each game the bot leaves the room, so it can be challenged
+// again.
+func (c *Client) Run(ctx context.Context, p Player) error {
+	stop := context.AfterFunc(ctx, func() { c.conn.Close() })
+	defer stop()
+
+	// played holds the round and turn of the last move, as a game state may
+	// come again for the same turn.
+	var played [2]int
+	for {
+		m, err := c.read()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		switch m.Action {
+		case protocol.Challenge:
+			ch, err := payload[protocol.ChallengePayload](m)
+			if err != nil {
+				return err
+			}
+			reply := protocol.ChallengeReplyPayload{ID: ch.ID}
+			action := protocol.AcceptChallenge
+			if ok, reason := c.cfg.accept(ch); !ok {
+				action, reply.Reason = protocol.DeclineChallenge, reason
+			}
+			c.cfg.logf("%v: %v challenges in room %v", action, ch.ChallengerName, ch.RoomName)
+			if err := c.send(action, reply); err != nil {
+				return err
+			}
+		case protocol.GameState:
+			state, err := payload[protocol.GameStatePayload](m)
+			if err != nil {
+				return err
+			}
+			if !c.toMove(state) || played == [2]int{state.Round, state.Turn} {
+				continue
+			}
+			point, err := p.Move(c.id, state)
+			if err != nil {
+				return fmt.Errorf("no move in %v: %w", state.Position, err)
+			}
+			played = [2]int{state.Round, state.Turn}
+			if err := c.send(protocol.MakeMove, protocol.MakeMovePayload{RoomUUID: m.Target, Point: point}); err != nil {
+				return err
+			}
+		case protocol.GameResult:
+			result, err := payload[protocol.GameResultPayload](m)
+			if err != nil {
+				return err
+			}
+			c.cfg.logf("game over in room %v, winner %q", m.Target, result.Winner)
+			if err := c.send(protocol.LeaveRoom, protocol.LeaveRoomPayload{RoomUUID: m.Target}); err != nil {
+				return err
+			}
+		case protocol.GameError:
+			c.cfg.logf("error: %v", m.Message)
+		}
+	}
+}
+
+// toMove reports whether the bot has a move to play in state. The server
+// also sends the states where the bot is about to pass or the game is over.
+func (c *Client) toMove(state protocol.GameStatePayload) bool {
+	if state.CurrentPlayer != c.id {
+		return false
+	}
+	for _, p := range state.Players {
+		if p.ID == c.id {
+			return len(p.PossibleMoves) > 0
+		}
+	}
+	return false
+}
+
+// read returns the next message of the server.
+func (c *Client) read() (protocol.Message, error) {
+	var m protocol.Message
+	err := c.conn.ReadJSON(&m)
+	return m, err
+}
+
+// send writes a client message.
+func (c *Client) send(action protocol.MessageType, message any) error {
+	return c.conn.WriteJSON(protocol.ClientMessage{Action: action, Message: message})
+}
+
+// payload decodes the payload of m, which comes as generic JSON values.
+func payload[T any](m protocol.Message) (T, error) {
+	var res T
+	b, err := json.Marshal(m.Message)
+	if err != nil {
+		return res, err
+	}
+	err = json.Unmarshal(b, &res)
+	return res, err
+}
+
+// computer plays like a reversi Computer player.
+type computer struct {
+	opts []reversi.PlayerCfgFunc
+	// players holds the Computer player of each token, kept across moves so
+	// the engines keep their tables.
+	players map[int]*reversi.Player
+}
+
+// NewComputer returns a Player choosing its moves like a reversi Computer
+// player made with opts, such as reversi.WithLevel(5) or
+// reversi.WithEngine(e). The engines play the standard board and the other
+// games get the greedy moves of a Computer player.
+func NewComputer(opts ...reversi.PlayerCfgFunc) Player {
+	return &computer{opts: opts, players: make(map[int]*reversi.Player)}
+}
+
+func (c *computer) Move(id string, state protocol.GameStatePayload) (reversi.Point, error) {
+	token := 0
+	for _, p := range state.Players {
+		if p.ID == id {
+			token = p.Token
+		}
+	}
+	if token == 0 {
+		return reversi.Point{}, errors.New("bot is not playing")
+	}
+	me, ok := c.players[token]
+	if !ok {
+		me = reversi.NewPlayer(token, append([]reversi.PlayerCfgFunc{reversi.WithPlayerType(reversi.Computer)}, c.opts...)...)
+		c.players[token] = me
+	}
+
+	players := make([]reversi.Player, len(state.Players))
+	for i := range players {
+		if i+1 == token {
+			players[i] = *me
+		} else {
+			players[i] = *reversi.NewPlayer(i + 1)
+		}
+	}
+	var cfgFuncs []reversi.GameCfgFunc
+	if len(players) > 2 {
+		cfgFuncs = append(cfgFuncs, reversi.WithMorePlayers(players[2:]...))
+	}
+	if state.AntiReversi {
+		cfgFuncs = append(cfgFuncs, reversi.WithObjective(reversi.FewestDiscs))
+	}
+	g, err := reversi.ParsePosition(state.Position, players[0], players[1], cfgFuncs...)
+	if err != nil {
+		return reversi.Point{}, err
+	}
+	return g.CurrentPlayer().ChooseMove(g)
+}
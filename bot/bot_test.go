@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/oscarhkli/reversi"
+	"github.com/oscarhkli/reversi/protocol"
+)
+
+// fakeServer accepts one bot and runs script on its connection.
+func fakeServer(t *testing.T, script func(conn *websocket.Conn)) string {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("bot") != "true" {
+			t.Errorf("bot flag not set in %v", r.URL)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		script(conn)
+	}))
+	t.Cleanup(s.Close)
+	return "ws" + strings.TrimPrefix(s.URL, "http") + "/ws"
+}
+
+func writeMessage(t *testing.T, conn *websocket.Conn, action protocol.MessageType, target string, message any) {
+	t.Helper()
+	if err := conn.WriteJSON(protocol.Message{Action: action, Target: target, Message: message}); err != nil {
+		t.Error(err)
+	}
+}
+
+// readMessage returns the next client message and its payload as T.
+func readMessage[T any](t *testing.T, conn *websocket.Conn) (protocol.MessageType, T) {
+	t.Helper()
+	var m protocol.ClientMessage
+	if err := conn.ReadJSON(&m); err != nil {
+		t.Fatal(err)
+	}
+	p, err := payload[T](protocol.Message{Message: m.Message})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m.Action, p
+}
+
+func TestClientPlaysChallenge(t *testing.T) {
+	const id, room = "bot-id", "room-id"
+	done := make(chan struct{})
+	endpoint := fakeServer(t, func(conn *websocket.Conn) {
+		defer close(done)
+		writeMessage(t, conn, protocol.RegisterResponse, "", protocol.RegisterResponsePayload{ID: id, Name: "Bot"})
+
+		writeMessage(t, conn, protocol.Challenge, "", protocol.ChallengePayload{ID: "c1", RoomUUID: room, ChallengerName: "Alice"})
+		action, reply := readMessage[protocol.ChallengeReplyPayload](t, conn)
+		if action != protocol.AcceptChallenge || reply.ID != "c1" {
+			t.Errorf("reply to challenge, want: %v c1, got %v %v", protocol.AcceptChallenge, action, reply.ID)
+		}
+
+		g := reversi.NewGameBoard(*reversi.NewPlayer(1), *reversi.NewPlayer(2))
+		state := protocol.GameStatePayload{
+			Round:         1,
+			CurrentPlayer: id,
+			Position:      g.String(),
+			Players: []protocol.PlayerPayload{
+				{ID: id, Token: 1, PossibleMoves: g.P1().PossibleMoves()},
+				{ID: "alice", Token: 2},
+			},
+		}
+		writeMessage(t, conn, protocol.GameState, room, state)
+		action, move := readMessage[protocol.MakeMovePayload](t, conn)
+		if action != protocol.MakeMove || move.RoomUUID != room || !g.P1().CanMove(move.Point) {
+			t.Errorf("move, want a legal move in %v, got %v %+v", room, action, move)
+		}
+
+		writeMessage(t, conn, protocol.GameResult, room, protocol.GameResultPayload{Winner: id})
+		action, leave := readMessage[protocol.LeaveRoomPayload](t, conn)
+		if action != protocol.LeaveRoom || leave.RoomUUID != room {
+			t.Errorf("after the game, want: %v %v, got %v %v", protocol.LeaveRoom, room, action, leave.RoomUUID)
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := Dial(ctx, endpoint, "Bot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.ID(), id; got != want {
+		t.Errorf("ID(), want: %v, got %v", want, got)
+	}
+	go c.Run(ctx, NewComputer(reversi.WithLevel(1)))
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("timed out")
+	}
+}
+
+func TestClientDeclinesChallenge(t *testing.T) {
+	done := make(chan struct{})
+	endpoint := fakeServer(t, func(conn *websocket.Conn) {
+		defer close(done)
+		writeMessage(t, conn, protocol.RegisterResponse, "", protocol.RegisterResponsePayload{ID: "bot-id", Name: "Bot"})
+		writeMessage(t, conn, protocol.Challenge, "", protocol.ChallengePayload{ID: "c1", Game: protocol.StartGamePayload{Size: 10}})
+		action, reply := readMessage[protocol.ChallengeReplyPayload](t, conn)
+		if action != protocol.DeclineChallenge || reply.Reason != "8x8 only" {
+			t.Errorf("reply to challenge, want: %v 8x8 only, got %v %v", protocol.DeclineChallenge, action, reply.Reason)
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := Dial(ctx, endpoint, "Bot", WithAccept(func(ch protocol.ChallengePayload) (bool, string) {
+		return ch.Game.Size == 0 || ch.Game.Size == reversi.Width, "8x8 only"
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	go c.Run(ctx, NewComputer())
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("timed out")
+	}
+}
+
+func TestComputerPlaysOtherBoards(t *testing.T) {
+	players := []reversi.Player{*reversi.NewPlayer(3), *reversi.NewPlayer(4)}
+	g := reversi.NewGameBoard(*reversi.NewPlayer(1), *reversi.NewPlayer(2), reversi.WithSize(10), reversi.WithMorePlayers(players...))
+	state := protocol.GameStatePayload{Position: g.String()}
+	for _, p := range g.Players() {
+		state.Players = append(state.Players, protocol.PlayerPayload{ID: p.ID().String(), Token: p.Token()})
+	}
+	current := g.CurrentPlayer()
+	move, err := NewComputer().Move(current.ID().String(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !current.CanMove(move) {
+		t.Errorf("Move(), want a legal move, got %v", move)
+	}
+}
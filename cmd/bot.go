@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/oscarhkli/reversi"
+	"github.com/oscarhkli/reversi/bot"
+)
+
+// runBot connects a Computer player to a server as a bot until interrupted.
+func runBot(args []string) {
+	fs := flag.NewFlagSet("bot", flag.ExitOnError)
+	endpoint := fs.String("url", "ws://"+addr+"/ws", "WebSocket endpoint of the server")
+	name := fs.String("name", "Bot", "name of the bot in the lobby")
+	level := fs.Int("level", reversi.DefaultLevel, "strength of the bot")
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	c, err := bot.Dial(ctx, *endpoint, *name, bot.WithLogf(log.Printf))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+	log.Printf("%v waiting for challenges as %v", c.Name(), c.ID())
+	if err := c.Run(ctx, bot.NewComputer(reversi.WithLevel(*level))); err != nil && ctx.Err() == nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/oscarhkli/reversi/protocol"
+)
+
+// challenge is a game offered to a bot, to be played in the room of the
+// challenger.
+type challenge struct {
+	id         string
+	room       *Room
+	challenger *Client
+	bot        *Client
+	game       protocol.StartGamePayload
+}
+
+func (h *Hub) addChallenge(ch *challenge) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.challenges[ch.id] = ch
+}
+
+// takeChallenge removes and returns the challenge with the given ID sent to
+// bot, nil if there is none.
+func (h *Hub) takeChallenge(id string, bot *Client) *challenge {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch, ok := h.challenges[id]
+	if !ok || ch.bot != bot {
+		return nil
+	}
+	delete(h.challenges, id)
+	return ch
+}
+
+// dropChallenges forgets the challenges of a client that left, telling the
+// challengers of a bot that left.
+func (h *Hub) dropChallenges(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id, ch := range h.challenges {
+		if ch.challenger != client && ch.bot != client {
+			continue
+		}
+		delete(h.challenges, id)
+		if ch.bot == client {
+			m := protocol.Message{
+				Action:  protocol.GameError,
+				Message: fmt.Sprintf("%s left before answering the challenge.", client.name),
+			}
+			select {
+			case ch.challenger.send <- m.Encode():
+			default:
+			}
+		}
+	}
+}
+
+// sendGameError tells c why its request failed.
+func (c *Client) sendGameError(text string) {
+	m := protocol.Message{
+		Action:  protocol.GameError,
+		Message: text,
+	}
+	c.send <- m.Encode()
+}
+
+// handleChallenge sends the challenge of cp to its bot. Challenges are
+// played in the room of the challenger, which must have no one else in it.
+// The room and the bot belong to other goroutines, so that is only checked
+// by startChallenge once the bot accepts.
+func (c *Client) handleChallenge(cp protocol.ChallengeRequestPayload) {
+	r := c.room
+	if r == nil {
+		c.sendGameError("Join a room before challenging a bot.")
+		return
+	}
+	bot := c.hub.lookupBot(cp.BotID)
+	if bot == nil {
+		c.sendGameError("There is no such bot.")
+		return
+	}
+
+	ch := &challenge{
+		id:         uuid.NewString(),
+		room:       r,
+		challenger: c,
+		bot:        bot,
+		game:       cp.Game,
+	}
+	// The challenger and the bot are the players.
+	ch.game.RoomUUID = r.uuid
+	ch.game.Players = 0
+	ch.game.Computer = nil
+	c.hub.addChallenge(ch)
+
+	m := protocol.Message{
+		Action: protocol.Challenge,
+		Message: protocol.ChallengePayload{
+			ID:             ch.id,
+			RoomUUID:       r.uuid,
+			RoomName:       r.name,
+			ChallengerID:   c.ID.String(),
+			ChallengerName: c.name,
+			Game:           ch.game,
+		},
+	}
+	bot.send <- m.Encode()
+}
+
+// handleChallengeReply accepts or declines the challenge of cr sent to the
+// bot c. An accepted challenge starts at once.
+func (c *Client) handleChallengeReply(cr protocol.ChallengeReplyPayload, accept bool) {
+	ch := c.hub.takeChallenge(cr.ID, c)
+	if ch == nil {
+		c.sendGameError("There is no such challenge.")
+		return
+	}
+	if !accept {
+		text := fmt.Sprintf("%s declined the challenge.", c.name)
+		if cr.Reason != "" {
+			text = fmt.Sprintf("%s declined the challenge: %s", c.name, cr.Reason)
+		}
+		ch.challenger.sendGameError(text)
+		return
+	}
+	if c.room != nil {
+		c.sendGameError("Leave your room before accepting a challenge.")
+		ch.challenger.sendGameError(fmt.Sprintf("%s is playing another game.", c.name))
+		return
+	}
+	c.room = ch.room
+	ch.room.accepted <- ch
+}
+
+// startChallenge seats the bot of an accepted challenge and starts the game,
+// unless the room changed since the challenge was sent.
+func (r *Room) startChallenge(ch *challenge) {
+	if len(r.clients) != 1 || !r.clients[ch.challenger] {
+		ch.bot.room = nil
+		ch.bot.sendGameError("The challenge is no longer open.")
+		if r.clients[ch.challenger] {
+			ch.challenger.sendGameError("Challenges are played in a room of your own.")
+		}
+		return
+	}
+	r.registerClientInRoom(ch.bot)
+	if err := r.startGame(ch.game); err != nil {
+		log.Printf("room %v: cannot start challenge %v: %v", r.uuid, ch.id, err)
+		m := &protocol.Message{
+			Action:  protocol.GameError,
+			Message: fmt.Sprintf("Cannot start the game: %v", err),
+		}
+		r.broadcastToClientsInRoom(m)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/oscarhkli/reversi/protocol"
+)
+
+// TestChallenge challenges a bot while other clients leave. Run it with -race
+// to check that only the hub goroutine reaches its clients.
+func TestChallenge(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	// Registering writes to the connection, so the clients are added before
+	// the hub runs.
+	hub := newHub(nil)
+	bot := NewClient(nil, hub, "bot", true)
+	hub.clients[bot] = true
+	var others []*Client
+	for i := 0; i < 20; i++ {
+		other := NewClient(nil, hub, "other", i%2 == 0)
+		hub.clients[other] = true
+		others = append(others, other)
+	}
+	go hub.run()
+	r := NewRoom("test")
+	go r.Run()
+	c := NewClient(nil, hub, "human", false)
+	c.room = r
+	r.register <- c
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, other := range others {
+			hub.unregister <- other
+		}
+	}()
+	c.handleChallenge(protocol.ChallengeRequestPayload{BotID: bot.ID.String()})
+	<-done
+
+	msg := nextMessage(t, bot, protocol.Challenge)
+	ch, err := unmarshalClientMessagePayload[protocol.ChallengePayload](msg.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch.RoomUUID != r.uuid || ch.ChallengerID != c.ID.String() {
+		t.Errorf("challenge, want room %v by %v, got %+v", r.uuid, c.ID, ch)
+	}
+	bot.handleChallengeReply(protocol.ChallengeReplyPayload{ID: ch.ID}, true)
+	nextState(t, c)
+	nextState(t, bot)
+}
+
+func TestChallengeRoomNotOwn(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	hub := newHub(nil)
+	bot := NewClient(nil, hub, "bot", true)
+	hub.clients[bot] = true
+	go hub.run()
+	r := NewRoom("test")
+	go r.Run()
+	c := NewClient(nil, hub, "human", false)
+	c.room = r
+	r.register <- c
+	other := NewClient(nil, hub, "other", false)
+	other.room = r
+	r.register <- other
+
+	c.handleChallenge(protocol.ChallengeRequestPayload{BotID: bot.ID.String()})
+	ch, err := unmarshalClientMessagePayload[protocol.ChallengePayload](nextMessage(t, bot, protocol.Challenge).Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot.handleChallengeReply(protocol.ChallengeReplyPayload{ID: ch.ID}, true)
+	nextMessage(t, c, protocol.GameError)
+}
@@ -5,10 +5,14 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+
+	"github.com/oscarhkli/reversi"
+	"github.com/oscarhkli/reversi/protocol"
 )
 
 const (
@@ -21,8 +25,9 @@ const (
 	// Send pings to peer with this period. Must be less than pongWait.
 	pingPeriod = (pongWait * 9) / 10
 
-	// Maximum message size allowed from peer.
-	maxMessageSize = 512
+	// Maximum message size allowed from peer. START_GAME may carry the
+	// position string of the largest board besides its other options.
+	maxMessageSize = reversi.MaxSize*reversi.MaxSize + 1024
 )
 
 var (
@@ -53,10 +58,15 @@ type Client struct {
 
 	// Rooms that client currently in
 	room *Room
+
+	// bot is set for programs playing through the bot API. Bots are listed
+	// in the lobby and can be challenged.
+	bot bool
 }
 
-func NewClient(conn *websocket.Conn, hub *Hub, name string) *Client {
+func NewClient(conn *websocket.Conn, hub *Hub, name string, bot bool) *Client {
 	return &Client{
+		bot:  bot,
 		name: name,
 		hub:  hub,
 		conn: conn,
@@ -153,21 +163,22 @@ func unmarshalClientMessagePayload[T any](message any) (T, error) {
 
 // handleNewMessage handles all client's action
 func (c *Client) handleNewMessage(jsonMsg []byte) {
-	var msg ClientMessage
+	var msg protocol.ClientMessage
 	log.Println(string(jsonMsg))
 	err := json.Unmarshal(jsonMsg, &msg)
 	if err != nil {
-		log.Fatalf("error in unmarshalling JSON message %s", err)
+		log.Printf("error in unmarshalling JSON message %s", err)
+		return
 	}
 
-	msg.Sender = c
+	msg.Sender = &protocol.Sender{ID: c.ID}
 
 	switch msg.Action {
-	case SendMessage:
+	case protocol.SendMessage:
 		r := c.room
 
 		if r != nil {
-			serverMsg := Message{
+			serverMsg := protocol.Message{
 				Action:  msg.Action,
 				Message: "",
 				Target:  msg.Target,
@@ -175,35 +186,47 @@ func (c *Client) handleNewMessage(jsonMsg []byte) {
 			}
 			r.broadcast <- &serverMsg
 		}
-	case JoinRoom:
-		if payload, err := unmarshalClientMessagePayload[JoinRoomPayload](msg.Message); err == nil {
+	case protocol.JoinRoom:
+		if payload, err := unmarshalClientMessagePayload[protocol.JoinRoomPayload](msg.Message); err == nil {
 			c.handleJoinRoomMessage(payload)
 		} else {
 			log.Println("Invalid message format for JoinRoom")
 		}
-	case LeaveRoom:
-		if payload, err := unmarshalClientMessagePayload[LeaveRoomPayload](msg.Message); err == nil {
+	case protocol.LeaveRoom:
+		if payload, err := unmarshalClientMessagePayload[protocol.LeaveRoomPayload](msg.Message); err == nil {
 			c.handleLeaveRoomMessage(payload)
 		} else {
 			log.Println("Invalid message format for LeaveRoom")
 		}
-	case StartGame:
-		if payload, err := unmarshalClientMessagePayload[StartGamePayload](msg.Message); err == nil {
+	case protocol.StartGame:
+		if payload, err := unmarshalClientMessagePayload[protocol.StartGamePayload](msg.Message); err == nil {
 			c.handleStartGameMessage(payload)
 		} else {
 			log.Println("Invalid message format for StartGame")
 		}
-	case MakeMove:
-		if payload, err := unmarshalClientMessagePayload[MakeMovePayload](msg.Message); err == nil {
+	case protocol.MakeMove:
+		if payload, err := unmarshalClientMessagePayload[protocol.MakeMovePayload](msg.Message); err == nil {
 			c.handleMakeMove(payload)
 		} else {
 			log.Println("Invalid message format for MakeMove")
 		}
+	case protocol.Challenge:
+		if payload, err := unmarshalClientMessagePayload[protocol.ChallengeRequestPayload](msg.Message); err == nil {
+			c.handleChallenge(payload)
+		} else {
+			log.Println("Invalid message format for Challenge")
+		}
+	case protocol.AcceptChallenge, protocol.DeclineChallenge:
+		if payload, err := unmarshalClientMessagePayload[protocol.ChallengeReplyPayload](msg.Message); err == nil {
+			c.handleChallengeReply(payload, msg.Action == protocol.AcceptChallenge)
+		} else {
+			log.Println("Invalid message format for challenge reply")
+		}
 	}
 }
 
 // handleJoinRoomMessage finds room by room UUID and join if exist. Othewise, new room will be created
-func (c *Client) handleJoinRoomMessage(jp JoinRoomPayload) {
+func (c *Client) handleJoinRoomMessage(jp protocol.JoinRoomPayload) {
 	r := c.hub.findRoomByUUID(jp.RoomUUID)
 	if r == nil {
 		r = c.hub.createRoom(jp.Name)
@@ -214,14 +237,14 @@ func (c *Client) handleJoinRoomMessage(jp JoinRoomPayload) {
 }
 
 // handleLeaveRoomMessage leave the room according to the room UUID
-func (c *Client) handleLeaveRoomMessage(lp LeaveRoomPayload) {
+func (c *Client) handleLeaveRoomMessage(lp protocol.LeaveRoomPayload) {
 	r := c.room
 	if r.uuid != lp.RoomUUID {
-		m := Message{
-			Action:  GameError,
+		m := protocol.Message{
+			Action:  protocol.GameError,
 			Message: "You are not in this room.",
 		}
-		c.send <- m.encode()
+		c.send <- m.Encode()
 		return
 	}
 
@@ -230,41 +253,32 @@ func (c *Client) handleLeaveRoomMessage(lp LeaveRoomPayload) {
 	r.unregister <- c
 }
 
-func (c *Client) handleStartGameMessage(sp StartGamePayload) {
+func (c *Client) handleStartGameMessage(sp protocol.StartGamePayload) {
 	r := c.room
 	if r.uuid != sp.RoomUUID {
-		m := Message{
-			Action:  GameError,
+		m := protocol.Message{
+			Action:  protocol.GameError,
 			Message: "You are not in this room.",
 		}
-		c.send <- m.encode()
-		return
-	}
-
-	if len(r.clients) < 2 {
-		m := Message{
-			Action:  GameError,
-			Message: "2 people are required to start the game.",
-		}
-		c.send <- m.encode()
+		c.send <- m.Encode()
 		return
 	}
 
-	r.startGame()
+	r.starts <- startRequest{client: c, payload: sp}
 }
 
-func (c *Client) handleMakeMove(mp MakeMovePayload) {
+func (c *Client) handleMakeMove(mp protocol.MakeMovePayload) {
 	r := c.room
 	if r.uuid != mp.RoomUUID {
-		m := Message{
-			Action:  GameError,
+		m := protocol.Message{
+			Action:  protocol.GameError,
 			Message: "You are not in this room.",
 		}
-		c.send <- m.encode()
+		c.send <- m.Encode()
 		return
 	}
 
-	r.handleMove(c, mp.Point)
+	r.moves <- clientMove{client: c, point: mp.Point}
 }
 
 // serveWs handles websocket requests from the peer.
@@ -284,7 +298,9 @@ func serveWs(hub *Hub, w http.ResponseWriter, r *http.Request) {
 		name = n[0]
 	}
 
-	client := NewClient(conn, hub, name)
+	// Bots connect with bot=true, e.g. /ws?name=Edax&bot=true.
+	bot, _ := strconv.ParseBool(r.URL.Query().Get("bot"))
+	client := NewClient(conn, hub, name, bot)
 	client.hub.register <- client
 
 	// Allow collection of memory referenced by the caller by doing all work in
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/oscarhkli/reversi"
+	"github.com/oscarhkli/reversi/protocol"
+)
+
+// TestStartLargestPosition starts a game from the position string of the
+// largest board, checking that the message fits in what clients may send.
+func TestStartLargestPosition(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	hub := newHub(nil)
+	go hub.run()
+	r := NewRoom("test")
+	go r.Run()
+	c := NewClient(nil, hub, "human", false)
+	c.room = r
+	r.register <- c
+
+	position := reversi.NewGameBoard(*reversi.NewPlayer(1), *reversi.NewPlayer(2), reversi.WithSize(reversi.MaxSize)).String()
+	b, err := json.Marshal(protocol.ClientMessage{
+		Action: protocol.StartGame,
+		Message: protocol.StartGamePayload{
+			RoomUUID:    r.uuid,
+			Position:    position,
+			Size:        reversi.MaxSize,
+			Computer:    &protocol.ComputerPayload{Level: reversi.MaxLevel, DelayMs: 1},
+			Hints:       protocol.HintScored,
+			AntiReversi: true,
+			Analysis:    true,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) > maxMessageSize {
+		t.Fatalf("START_GAME message, want at most %v bytes, got %v", maxMessageSize, len(b))
+	}
+	c.handleNewMessage(b)
+	state := nextState(t, c)
+	if state.Size != reversi.MaxSize || state.Position != position {
+		t.Errorf("game state, want the %dx%d position, got size %v", reversi.MaxSize, reversi.MaxSize, state.Size)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/oscarhkli/reversi"
+)
+
+// runConsole plays games on the terminal.
+func runConsole(args []string) {
+	fs := flag.NewFlagSet("console", flag.ExitOnError)
+	bookFile := fs.String("book", "", "opening book for Computer players")
+	bookMoves := fs.Int("book-moves", 12, "number of moves to play from the opening book")
+	save := fs.String("save", "", "file to append the GGF record of every game to")
+	position := fs.String("position", "", "position string to start every game from")
+	size := fs.Int("size", reversi.Width, "number of rows and columns of the board")
+	anti := fs.Bool("anti", false, "play anti-reversi, where the player with fewer discs wins")
+	handicap := fs.Int("handicap", 0, "number of corner discs player -handicap-player starts with")
+	handicapPlayer := fs.Int("handicap-player", 1, "player receiving the handicap, 1 or 2")
+	fs.Parse(args)
+
+	var opts []reversi.PlayerCfgFunc
+	var gameOpts []reversi.GameCfgFunc
+	if *bookFile != "" {
+		book, err := reversi.LoadBookFile(*bookFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		opts = append(opts, reversi.WithOpeningBook(book, *bookMoves))
+	}
+	if *size != reversi.Width {
+		if *size < reversi.MinSize || *size > reversi.MaxSize || *size%2 != 0 {
+			fmt.Fprintf(os.Stderr, "board size must be even, from %d to %d\n", reversi.MinSize, reversi.MaxSize)
+			os.Exit(1)
+		}
+		gameOpts = append(gameOpts, reversi.WithSize(*size))
+	}
+	if *anti {
+		gameOpts = append(gameOpts, reversi.WithObjective(reversi.FewestDiscs))
+	}
+	if *handicap != 0 {
+		if *handicap < 1 || *handicap > reversi.MaxHandicap || *handicapPlayer < 1 || *handicapPlayer > 2 {
+			fmt.Fprintf(os.Stderr, "a handicap is 1 to %d corners for player 1 or 2\n", reversi.MaxHandicap)
+			os.Exit(1)
+		}
+		gameOpts = append(gameOpts, reversi.WithHandicap(*handicapPlayer, *handicap))
+	}
+	amain(*save, *position, gameOpts, opts...)
+}
+
+// createPlayer asks for the settings of a player. opts are applied to
+// Computer players.
+func createPlayer(token int, opts ...reversi.PlayerCfgFunc) *reversi.Player {
+	fmt.Printf("Settings for Player %d\n", token)
+	fmt.Printf("Name (empty for default name): ")
+	var name string
+	fmt.Scanln(&name)
+
+	fmt.Printf("Is Player %d a human (y/n/others = human): ", token)
+	var isHuman string
+	fmt.Scanln(&isHuman)
+
+	if isHuman != "n" {
+		return reversi.NewPlayer(token, reversi.WithName(name), reversi.WithPlayerType(reversi.Human))
+	}
+
+	fmt.Printf("Level of Player %d (%d-%d, empty for %d): ", token, reversi.MinLevel, reversi.MaxLevel, reversi.DefaultLevel)
+	var level int
+	fmt.Scanln(&level)
+	opts = append([]reversi.PlayerCfgFunc{reversi.WithName(name), reversi.WithPlayerType(reversi.Computer), reversi.WithLevel(level)}, opts...)
+	return reversi.NewPlayer(token, opts...)
+}
+
+// amain plays games until the user stops on the board set up by gameOpts.
+// Games start from position unless it is empty. The record of every game is
+// appended to the file save unless it is empty.
+func amain(save, position string, gameOpts []reversi.GameCfgFunc, opts ...reversi.PlayerCfgFunc) {
+	p1, p2 := createPlayer(1, opts...), createPlayer(2, opts...)
+
+	killGame := false
+	round := 1
+
+	var g *reversi.GameBoard
+
+	for !killGame {
+		cfgFuncs := append([]reversi.GameCfgFunc{reversi.WithP1First(round%2 == 1), reversi.WithShowHint(true)}, gameOpts...)
+		g = reversi.NewGameBoard(*p1, *p2, cfgFuncs...)
+		if position != "" {
+			var err error
+			if g, err = reversi.ParsePosition(position, *p1, *p2, cfgFuncs...); err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+		}
+		info := reversi.GameInfo{Place: "console", Date: time.Now()}
+
+		for !g.EndGame() {
+			g.Print()
+			currPlayer := g.CurrentPlayer()
+			if g.MustPass() {
+				fmt.Printf("%v has no possible moves and passes.\n", currPlayer.Name())
+				g.Pass()
+				continue
+			}
+
+			point, err := currPlayer.ChooseMove(g)
+			if err != nil {
+				fmt.Println(err.Error())
+				continue
+			}
+
+			flips, err := g.Mark(point, *currPlayer)
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			notation, err := point.ToNotationOn(g.Size())
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+
+			fmt.Printf("%v chooses %v and flips %v disks\n", currPlayer.Name(), notation, flips)
+		}
+
+		g.Print()
+
+		winner := g.Result()
+		if winner == nil {
+			fmt.Println("Draw Game")
+		} else {
+			fmt.Println("Winner is", winner.Name())
+		}
+		fmt.Println("Transcript:", g.Transcript())
+		fmt.Println("Position:", g)
+		if save != "" {
+			if err := appendRecord(save, g.GGF(info)); err != nil {
+				fmt.Println(err.Error())
+			}
+		}
+
+		fmt.Printf("Play again? ([y]/n): ")
+		var again string
+		fmt.Scanln(&again)
+		killGame = (again == "n")
+	}
+}
+
+// appendRecord adds record as a line to the file name.
+func appendRecord(name, record string) error {
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, record); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
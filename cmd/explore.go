@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/oscarhkli/reversi"
+)
+
+// runExplore prints how the moves of a position were played in WTHOR
+// databases, e.g.
+//
+//	explore -moves f5d6 WTH_2001.wtb WTH_2002.wtb
+func runExplore(args []string) {
+	fs := flag.NewFlagSet("explore", flag.ExitOnError)
+	moves := fs.String("moves", "", "transcript leading to the position (default the starting position)")
+	fs.Parse(args)
+
+	d := reversi.NewGameDatabase()
+	for _, name := range fs.Args() {
+		games, err := reversi.LoadWthorFile(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", name, err)
+			os.Exit(1)
+		}
+		for _, g := range games {
+			if err := d.Add(g); err != nil {
+				fmt.Fprintf(os.Stderr, "%v: %v\n", name, err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	g, err := reversi.ParseTranscript(*moves)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%d games\n", d.Len())
+	for _, s := range d.Stats(g) {
+		notation, _ := g.StandardNotation(s.Move)
+		fmt.Printf("%v %7d games %6.1f%%\n", notation, s.Games, 100*s.WinRate())
+	}
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"log"
+	"sync"
+
+	"github.com/oscarhkli/reversi/protocol"
 )
 
 // Hub maintains the set of active clients and broadcasts messages to the clients.
@@ -18,15 +21,30 @@ type Hub struct {
 	// Unregister requests from clients.
 	unregister chan *Client
 	rooms      map[*Room]bool
+
+	// Bot lookups from the clients, answered by run as it owns clients.
+	findBot chan botLookup
+
+	// engine is the command and arguments of the NBoard engine rooms may
+	// seat as their computer player, empty if none.
+	engine []string
+
+	// challenges holds the challenges sent to bots and not yet answered, by
+	// ID. Clients reach it from their own goroutines, hence mu.
+	mu         sync.Mutex
+	challenges map[string]*challenge
 }
 
-func newHub() *Hub {
+func newHub(engine []string) *Hub {
 	return &Hub{
+		engine:     engine,
 		broadcast:  make(chan []byte),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		findBot:    make(chan botLookup),
 		clients:    make(map[*Client]bool),
 		rooms:      make(map[*Room]bool),
+		challenges: make(map[string]*challenge),
 	}
 }
 
@@ -37,6 +55,8 @@ func (h *Hub) run() {
 			h.registerClient(client)
 		case client := <-h.unregister:
 			h.unregisterClient(client)
+		case l := <-h.findBot:
+			l.reply <- h.findBotByID(l.id)
 		case message := <-h.broadcast:
 			for client := range h.clients {
 				select {
@@ -54,24 +74,35 @@ func (h *Hub) registerClient(client *Client) {
 	h.clients[client] = true
 	log.Printf("new client joined: %s", client.ID)
 
-	rooms := []RoomUpdatedPayload{}
+	rooms := []protocol.RoomUpdatedPayload{}
 	for room := range h.rooms {
-		rooms = append(rooms, RoomUpdatedPayload{
+		rooms = append(rooms, protocol.RoomUpdatedPayload{
 			RoomUUID: room.uuid,
 			Name:     room.name,
 			Count:    len(room.clients),
 		})
 	}
 
-	m := Message{
-		Action: RegisterResponse,
-		Message: RegisterResponsePayload{
+	bots := []protocol.BotPayload{}
+	for c := range h.clients {
+		if c.bot && c != client {
+			bots = append(bots, protocol.BotPayload{ID: c.ID.String(), Name: c.name})
+		}
+	}
+
+	m := protocol.Message{
+		Action: protocol.RegisterResponse,
+		Message: protocol.RegisterResponsePayload{
 			ID:    client.ID.String(),
 			Name:  client.name,
 			Rooms: rooms,
+			Bots:  bots,
 		},
 	}
-	client.conn.WriteMessage(1, m.encode())
+	client.conn.WriteMessage(1, m.Encode())
+	if client.bot {
+		h.broadcastBotUpdated(client, "JOINED")
+	}
 }
 
 func (h *Hub) unregisterClient(client *Client) {
@@ -79,7 +110,59 @@ func (h *Hub) unregisterClient(client *Client) {
 		delete(h.clients, client)
 		close(client.send)
 		log.Printf("client left: %s", client.ID)
+		h.dropChallenges(client)
+		if client.bot {
+			h.broadcastBotUpdated(client, "LEFT")
+		}
+	}
+}
+
+// broadcastBotUpdated tells every client that bot joined or left. It runs on
+// the hub goroutine, so it cannot go through h.broadcast.
+func (h *Hub) broadcastBotUpdated(bot *Client, action string) {
+	m := protocol.Message{
+		Action: protocol.BotUpdated,
+		Message: protocol.BotPayload{
+			ID:     bot.ID.String(),
+			Name:   bot.name,
+			Action: action,
+		},
 	}
+	message := m.Encode()
+	for client := range h.clients {
+		if client == bot {
+			continue
+		}
+		select {
+		case client.send <- message:
+		default:
+			close(client.send)
+			delete(h.clients, client)
+		}
+	}
+}
+
+// botLookup asks run for the bot with the given ID.
+type botLookup struct {
+	id    string
+	reply chan *Client
+}
+
+// lookupBot returns the bot with the given ID, nil if none. Clients call it
+// from their own goroutines, so the search is left to run.
+func (h *Hub) lookupBot(id string) *Client {
+	reply := make(chan *Client, 1)
+	h.findBot <- botLookup{id: id, reply: reply}
+	return <-reply
+}
+
+func (h *Hub) findBotByID(id string) *Client {
+	for c := range h.clients {
+		if c.bot && c.ID.String() == id {
+			return c
+		}
+	}
+	return nil
 }
 
 func (h *Hub) findRoomByUUID(uuid string) *Room {
@@ -96,6 +179,7 @@ func (h *Hub) findRoomByUUID(uuid string) *Room {
 
 func (h *Hub) createRoom(name string) *Room {
 	r := NewRoom(name)
+	r.engine = h.engine
 	go r.Run()
 	h.rooms[r] = true
 
@@ -103,14 +187,14 @@ func (h *Hub) createRoom(name string) *Room {
 }
 
 func (h *Hub) broadcastRoomUpdated(r *Room, action string) {
-	m := Message{
-		Action: RoomUpdated,
-		Message: RoomUpdatedPayload{
+	m := protocol.Message{
+		Action: protocol.RoomUpdated,
+		Message: protocol.RoomUpdatedPayload{
 			RoomUUID: r.uuid,
 			Action:   action,
 			Name:     r.name,
 			Count:    len(r.clients),
 		},
 	}
-	h.broadcast <- m.encode()
+	h.broadcast <- m.Encode()
 }
@@ -1,18 +1,56 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"net/http"
+	"os"
+	"strings"
 	"text/template"
 	"time"
+
+	"github.com/oscarhkli/reversi/protocol"
 )
 
 const addr = "localhost:8080"
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "solve":
+			runSolve(os.Args[2:])
+			return
+		case "explore":
+			runExplore(os.Args[2:])
+			return
+		case "console":
+			runConsole(os.Args[2:])
+			return
+		case "bot":
+			runBot(os.Args[2:])
+			return
+		case "tournament":
+			runTournament(os.Args[2:])
+			return
+		case "selfplay":
+			runSelfPlay(os.Args[2:])
+			return
+		case "train":
+			runTrain(os.Args[2:])
+			return
+		}
+	}
+	serve(os.Args[1:])
+}
+
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	engine := flags.String("engine", "", "command of an NBoard engine rooms may play against")
+	flags.Parse(args)
+
 	log.Printf("listening on ws://%v", addr)
 
-	hub := newHub()
+	hub := newHub(strings.Fields(*engine))
 	go hub.run()
 
 	fs := http.FileServer(http.Dir("./web/dist"))
@@ -33,6 +71,16 @@ func main() {
 		tmpl.Execute(w, data)
 	})
 
+	http.HandleFunc("/schema.json", func(w http.ResponseWriter, r *http.Request) {
+		schema, err := protocol.Schema()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/schema+json")
+		w.Write(schema)
+	})
+
 	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		serveWs(hub, w, r)
 	})
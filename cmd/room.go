@@ -1,10 +1,33 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
+	"os"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/oscarhkli/reversi"
+	"github.com/oscarhkli/reversi/protocol"
+)
+
+const (
+	// balancedMoves is the length of the random openings of the Balanced
+	// variant.
+	balancedMoves = 8
+	// obstacleCount is the number of cells blocked by the Obstacles variant.
+	obstacleCount = 4
+	// multiPlayerSize is the board size of games of more than two players
+	// unless the room asks for another.
+	multiPlayerSize = 10
+	// hintDepth is the depth of the searches behind scored hints.
+	hintDepth = 4
+	// defaultComputerDelay and maxComputerDelay bound the time a computer
+	// player seems to think before each move.
+	defaultComputerDelay = 500 * time.Millisecond
+	maxComputerDelay     = 10 * time.Second
 )
 
 type Room struct {
@@ -13,20 +36,74 @@ type Room struct {
 	clients    map[*Client]bool
 	register   chan *Client
 	unregister chan *Client
-	broadcast  chan *Message
-	gameBoard  *GameBoard
+	broadcast  chan *protocol.Message
+	gameBoard  *reversi.GameBoard
 	round      int
+	// opening is the name of the last named opening the game went through.
+	opening string
+	// analysis adds the Analysis of the position to the game state.
+	analysis bool
+	// hints is the help given to the player to move. Scored hints are
+	// searched on the goroutine running Run, which plays every move of the
+	// room, so a search only holds up this room and never the clients.
+	hints protocol.HintLevel
+	// positions maps the hash of every position played in the room to the
+	// round it first appeared in.
+	positions map[uint64]int
+	// computerDelay is the least time the computer player takes per move.
+	computerDelay time.Duration
+	// computerMoves carries the moves of the computer player back to Run.
+	computerMoves chan computerMove
+	// engine is the command of the NBoard engine the room may seat, and
+	// external the running engine of the current game, nil if none.
+	engine   []string
+	external *reversi.NBoardEngine
+	// accepted carries the challenges accepted by bots to Run.
+	accepted chan *challenge
+	// starts and moves carry the games started and the moves made by the
+	// clients of the room to Run, so that the board only changes there.
+	starts chan startRequest
+	moves  chan clientMove
+}
+
+// startRequest asks for a game with the options of payload on behalf of
+// client.
+type startRequest struct {
+	client  *Client
+	payload protocol.StartGamePayload
+}
+
+// clientMove is a move made by client.
+type clientMove struct {
+	client *Client
+	point  reversi.Point
+}
+
+// computerMove is a move chosen by the computer player of the room at the
+// given round and turn. It is dropped if the game has moved on since. The
+// computer resigns if it could not choose a move.
+type computerMove struct {
+	id     uuid.UUID
+	round  int
+	turn   int
+	point  reversi.Point
+	resign bool
 }
 
 func NewRoom(name string) *Room {
 	return &Room{
-		name:       name,
-		uuid:       uuid.NewString(),
-		clients:    make(map[*Client]bool),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-		broadcast:  make(chan *Message),
-		round:      0,
+		name:          name,
+		uuid:          uuid.NewString(),
+		clients:       make(map[*Client]bool),
+		register:      make(chan *Client),
+		unregister:    make(chan *Client),
+		broadcast:     make(chan *protocol.Message),
+		round:         0,
+		positions:     make(map[uint64]int),
+		computerMoves: make(chan computerMove),
+		accepted:      make(chan *challenge),
+		starts:        make(chan startRequest),
+		moves:         make(chan clientMove),
 	}
 }
 
@@ -39,6 +116,26 @@ func (r *Room) Run() {
 			r.unregisterClientInRoom(client)
 		case message := <-r.broadcast:
 			r.broadcastToClientsInRoom(message)
+		case ch := <-r.accepted:
+			r.startChallenge(ch)
+		case req := <-r.starts:
+			r.handleStartGame(req.client, req.payload)
+		case m := <-r.moves:
+			if r.gameBoard == nil {
+				m.client.sendGameError("No game is being played in this room.")
+				break
+			}
+			r.handleMove(m.client.ID, m.point)
+		case m := <-r.computerMoves:
+			if r.gameBoard == nil || r.round != m.round || r.gameBoard.Turn() != m.turn {
+				break
+			}
+			if m.resign {
+				r.gameBoard.Surrender(r.gameBoard.CurrentPlayer())
+				r.announceWinner()
+				break
+			}
+			r.handleMove(m.id, m.point)
 		}
 	}
 }
@@ -52,10 +149,7 @@ func (r *Room) registerClientInRoom(client *Client) {
 
 func (r *Room) unregisterClientInRoom(client *Client) {
 	if _, ok := r.clients[client]; ok {
-		if r.gameBoard != nil {
-			r.handleSurrender(client)
-			r.gameBoard = nil
-		}
+		r.handleSurrender(client)
 		delete(r.clients, client)
 		client.hub.broadcastRoomUpdated(r, "UPDATED")
 		r.notifyClientLeaveRoomResult(client)
@@ -63,27 +157,27 @@ func (r *Room) unregisterClientInRoom(client *Client) {
 	}
 }
 
-func (r *Room) broadcastToClientsInRoom(m *Message) {
+func (r *Room) broadcastToClientsInRoom(m *protocol.Message) {
 	for client := range r.clients {
-		client.send <- m.encode()
+		client.send <- m.Encode()
 	}
 }
 
 func (r *Room) notifyClientJoinRoomResult(client *Client) {
-	message := Message{
-		Action: JoinRoomResponse,
-		Message: JoinRoomPayload{
+	message := protocol.Message{
+		Action: protocol.JoinRoomResponse,
+		Message: protocol.JoinRoomPayload{
 			RoomUUID: r.uuid,
 			Name:     r.name,
 		},
 	}
-	client.send <- message.encode()
+	client.send <- message.Encode()
 }
 
 // notifyClientJoined broadcasts message to the room about new client joined
 func (r *Room) notifyClientJoined(client *Client) {
-	message := &Message{
-		Action:  SendMessage,
+	message := &protocol.Message{
+		Action:  protocol.SendMessage,
 		Target:  r.uuid,
 		Message: fmt.Sprintf("%s join the room", client.name),
 	}
@@ -97,15 +191,15 @@ func (r *Room) notifyClientLeaveRoomResult(client *Client) {
 			log.Printf("Recovered in notifyClientLeaveRoomResult: %v", r)
 		}
 	}()
-	message := Message{
-		Action: LeaveRoomResponse,
-		Message: LeaveRoomPayload{
+	message := protocol.Message{
+		Action: protocol.LeaveRoomResponse,
+		Message: protocol.LeaveRoomPayload{
 			RoomUUID: r.uuid,
 		},
 	}
 
 	select {
-	case client.send <- message.encode():
+	case client.send <- message.Encode():
 	default:
 		log.Printf("client %v send closed", client.ID)
 	}
@@ -113,8 +207,8 @@ func (r *Room) notifyClientLeaveRoomResult(client *Client) {
 
 // notifyClientLeft broadcasts message to the room about a client left
 func (r *Room) notifyClientLeft(client *Client) {
-	message := &Message{
-		Action:  SendMessage,
+	message := &protocol.Message{
+		Action:  protocol.SendMessage,
 		Target:  r.uuid,
 		Message: fmt.Sprintf("%s left the room", client.name),
 	}
@@ -127,72 +221,321 @@ func (r *Room) handleSurrender(client *Client) {
 		return
 	}
 
-	if r.gameBoard.p1.id == client.ID {
-		r.gameBoard.p1.surrender = true
-	} else {
-		r.gameBoard.p2.surrender = true
+	for _, p := range r.gameBoard.Players() {
+		if p.ID() == client.ID {
+			r.gameBoard.Surrender(p)
+		}
 	}
 	r.announceWinner()
 }
 
-func (r *Room) startGame() {
-	log.Println("startGame")
-	r.round++
-	var p1, p2 *Player
-	for c := range r.clients {
-		if p1 == nil {
-			p1 = NewPlayer(1, WithID(c.ID), WithName(c.name))
-			continue
+// handleStartGame starts a new round with the options of sp for client, or
+// tells client why it cannot.
+func (r *Room) handleStartGame(client *Client, sp protocol.StartGamePayload) {
+	if sp.Computer == nil && len(r.clients) < 2 {
+		m := protocol.Message{
+			Action:  protocol.GameError,
+			Message: "2 people are required to start the game.",
 		}
-		if p2 == nil {
-			p2 = NewPlayer(2, WithID(c.ID), WithName(c.name))
+		client.send <- m.Encode()
+		return
+	}
+
+	if err := r.startGame(sp); err != nil {
+		m := protocol.Message{
+			Action:  protocol.GameError,
+			Message: fmt.Sprintf("Cannot start the game: %v", err),
 		}
+		client.send <- m.Encode()
 	}
+}
 
-	if p1 == nil || p2 == nil {
-		log.Println("player is missing")
-		return
+// startGame starts a new round with the options of sp.
+func (r *Room) startGame(sp protocol.StartGamePayload) error {
+	log.Println("startGame")
+	players, engine, err := r.seatPlayers(sp.Players, sp.Computer)
+	if err != nil {
+		return err
+	}
+	if engine != nil {
+		// The engine is only kept if the game starts.
+		defer func() {
+			if r.external != engine {
+				go engine.Close()
+			}
+		}()
+	}
+	delay := defaultComputerDelay
+	if sp.Computer != nil && sp.Computer.DelayMs != 0 {
+		delay = time.Duration(sp.Computer.DelayMs) * time.Millisecond
+		if delay < 0 || delay > maxComputerDelay {
+			return fmt.Errorf("a computer delay is 0 to %v, not %v", maxComputerDelay, delay)
+		}
+	}
+	p1, p2 := players[0], players[1]
+	log.Println(players)
+	hints := sp.Hints
+	switch hints {
+	case "":
+		hints = protocol.HintMoves
+	case protocol.HintNone, protocol.HintMoves, protocol.HintScored:
+	default:
+		return fmt.Errorf("unknown hint level %q", hints)
 	}
-	log.Println(p1, p2)
-	r.gameBoard = NewGameBoard(*p1, *p2, WithP1First(r.round%2 == 1), WithShowHint(true))
-	m := &Message{
-		Action:  SendMessage,
+	p1First := (r.round+1)%2 == 1
+	cfgFuncs := []reversi.GameCfgFunc{reversi.WithP1First(p1First), reversi.WithShowHint(hints != protocol.HintNone)}
+	if len(players) > 2 {
+		more := make([]reversi.Player, 0, len(players)-2)
+		for _, p := range players[2:] {
+			more = append(more, *p)
+		}
+		cfgFuncs = append(cfgFuncs, reversi.WithMorePlayers(more...))
+		if sp.Size == 0 {
+			sp.Size = multiPlayerSize
+		}
+		if sp.Variant == protocol.Balanced || sp.Handicap != nil {
+			return errors.New("games of more than two players have no balanced openings or handicaps")
+		}
+	}
+	if sp.Size != 0 {
+		cfgFuncs = append(cfgFuncs, reversi.WithSize(sp.Size))
+	}
+	if sp.AntiReversi {
+		cfgFuncs = append(cfgFuncs, reversi.WithObjective(reversi.FewestDiscs))
+	}
+	if sp.Handicap != nil {
+		handicap, err := handicapCfg(*sp.Handicap, p1, p2)
+		if err != nil {
+			return err
+		}
+		if sp.Position != "" {
+			return errors.New("a handicap cannot be given with a starting position")
+		}
+		cfgFuncs = append(cfgFuncs, handicap)
+	}
+	variant, err := variantCfg(sp, p1First)
+	if err != nil {
+		return err
+	}
+	cfgFuncs = append(cfgFuncs, variant...)
+	g := reversi.NewGameBoard(*p1, *p2, cfgFuncs...)
+	if sp.Size != 0 && g.Size() != sp.Size {
+		return fmt.Errorf("unsupported board size %d", sp.Size)
+	}
+	if sp.Position != "" {
+		if g, err = reversi.ParsePosition(sp.Position, *p1, *p2, cfgFuncs...); err != nil {
+			return err
+		}
+	}
+	r.round++
+	r.gameBoard = g
+	r.opening = ""
+	r.analysis = sp.Analysis
+	r.hints = hints
+	r.computerDelay = delay
+	r.stopEngine()
+	r.external = engine
+	log.Printf("room %v round %v: start from %v", r.uuid, r.round, g)
+	m := &protocol.Message{
+		Action:  protocol.SendMessage,
 		Message: "Game Start!",
 		Target:  r.uuid,
 	}
 	r.broadcastToClientsInRoom(m)
 	r.broadcastGameState()
+	r.playComputer()
+	return nil
 }
 
-// broadcastGameState. To broadcast the game state to all clients in the room for render the board data
-func (r *Room) broadcastGameState() {
-	getPossibleMoves := func(m map[Point][]Point) []Point {
-		var res []Point
-		for p := range m {
-			res = append(res, p)
+// variantCfg returns the options setting up the variant and blocked cells of
+// sp for a game where player 1 moves first if p1First is set.
+func variantCfg(sp protocol.StartGamePayload, p1First bool) ([]reversi.GameCfgFunc, error) {
+	size := sp.Size
+	if size == 0 {
+		size = reversi.Width
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	var cfgFuncs []reversi.GameCfgFunc
+	switch sp.Variant {
+	case "", protocol.Standard:
+	case protocol.Balanced:
+		if size != reversi.Width {
+			return nil, fmt.Errorf("the %v variant is only played on the %dx%d board", sp.Variant, reversi.Width, reversi.Height)
+		}
+		layout := reversi.RandomBalancedLayout(rng, balancedMoves)
+		if !p1First {
+			// The opening was drawn with player 1 to move.
+			for _, row := range layout {
+				for x, cell := range row {
+					if cell > 0 {
+						row[x] = 3 - cell
+					}
+				}
+			}
+		}
+		cfgFuncs = append(cfgFuncs, reversi.WithLayout(layout))
+	case protocol.Obstacles:
+		cfgFuncs = append(cfgFuncs, reversi.WithBlocked(reversi.RandomObstacles(rng, size, obstacleCount)...))
+	default:
+		return nil, fmt.Errorf("unknown variant %q", sp.Variant)
+	}
+	if len(sp.Blocked) > 0 {
+		cfgFuncs = append(cfgFuncs, reversi.WithBlocked(sp.Blocked...))
+	}
+	return cfgFuncs, nil
+}
+
+// seatPlayers picks n of the clients in the room, 2 if n is 0, as the players
+// of a new game in the order of their tokens. If computer is set, the last
+// seat goes to a computer player instead, along with the external engine it
+// plays with, if any.
+func (r *Room) seatPlayers(n int, computer *protocol.ComputerPayload) ([]*reversi.Player, *reversi.NBoardEngine, error) {
+	if n == 0 {
+		n = 2
+	}
+	if n < 2 || n > reversi.MaxPlayers {
+		return nil, nil, fmt.Errorf("a game is played by 2 to %d players, not %d", reversi.MaxPlayers, n)
+	}
+	clients := n
+	if computer != nil {
+		clients--
+	}
+	players := make([]*reversi.Player, 0, n)
+	for c := range r.clients {
+		if len(players) == clients {
+			break
 		}
-		return res
+		players = append(players, reversi.NewPlayer(len(players)+1, reversi.WithID(c.ID), reversi.WithName(c.name)))
+	}
+	if len(players) < clients {
+		return nil, nil, fmt.Errorf("%d players are required, the room has %d", clients, len(players))
+	}
+	if computer == nil {
+		return players, nil, nil
+	}
+	level := computer.Level
+	if level == 0 {
+		level = reversi.DefaultLevel
+	}
+	if level < reversi.MinLevel || level > reversi.MaxLevel {
+		return nil, nil, fmt.Errorf("a computer level is %d to %d, not %d", reversi.MinLevel, reversi.MaxLevel, level)
+	}
+	opts := []reversi.PlayerCfgFunc{
+		reversi.WithPlayerType(reversi.Computer),
+		reversi.WithLevel(level),
+		reversi.WithName(fmt.Sprintf("Computer (level %d)", level)),
+	}
+	var engine *reversi.NBoardEngine
+	if computer.Engine {
+		if len(r.engine) == 0 {
+			return nil, nil, errors.New("the server has no external engine")
+		}
+		var err error
+		engine, err = reversi.StartNBoardEngine(r.engine[0], r.engine[1:], reversi.WithNBoardStderr(os.Stderr))
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, reversi.WithEngine(engine), reversi.WithName(engine.Name()))
+	}
+	players = append(players, reversi.NewPlayer(n, opts...))
+	return players, engine, nil
+}
+
+// stopEngine stops the NBoard engine of the last game, if any. It closes in
+// the background as it may still be thinking.
+func (r *Room) stopEngine() {
+	if r.external != nil {
+		go r.external.Close()
+		r.external = nil
 	}
+}
 
-	constructPlayerPayload := func(p *Player) PlayerPayload {
-		return PlayerPayload{
-			ID:            p.id.String(),
-			Name:          p.name,
-			Token:         p.token,
-			Score:         p.score,
-			PossibleMoves: getPossibleMoves(p.possibleMoves),
+// handicapCfg gives the corners of hp to whichever of p1 and p2 it names.
+func handicapCfg(hp protocol.HandicapPayload, p1, p2 *reversi.Player) (reversi.GameCfgFunc, error) {
+	if hp.Corners < 1 || hp.Corners > reversi.MaxHandicap {
+		return nil, fmt.Errorf("a handicap is 1 to %d corners, not %d", reversi.MaxHandicap, hp.Corners)
+	}
+	for _, p := range []*reversi.Player{p1, p2} {
+		if p.ID().String() == hp.Player {
+			return reversi.WithHandicap(p.Token(), hp.Corners), nil
 		}
 	}
-	log.Println(r.gameBoard.CurrentPlayer().id)
-	m := &Message{
-		Action: GameState,
-		Message: GameStatePayload{
-			P1:            constructPlayerPayload(r.gameBoard.p1),
-			P2:            constructPlayerPayload(r.gameBoard.p2),
+	return nil, fmt.Errorf("handicap player %q is not playing", hp.Player)
+}
+
+// handicapPayload returns the handicap of the game in the room, nil if none.
+func (r *Room) handicapPayload() *protocol.HandicapPayload {
+	h := r.gameBoard.Handicap()
+	if h.Corners == 0 {
+		return nil
+	}
+	p := r.gameBoard.P1()
+	if h.Token == 2 {
+		p = r.gameBoard.P2()
+	}
+	return &protocol.HandicapPayload{Player: p.ID().String(), Corners: h.Corners}
+}
+
+// playerPayloads returns the payloads of every player in the order of their
+// tokens, with the scored hints of the player to move if the room gives them.
+// It runs on the goroutine of Run, like every change to the board.
+func (r *Room) playerPayloads() []protocol.PlayerPayload {
+	var res []protocol.PlayerPayload
+	for _, p := range r.gameBoard.Players() {
+		res = append(res, newPlayerPayload(p))
+	}
+	if r.hints == protocol.HintScored && !r.gameBoard.EndGame() {
+		current := r.gameBoard.CurrentPlayer().Token()
+		res[current-1].Hints = r.gameBoard.Hints(hintDepth)
+	}
+	return res
+}
+
+func newPlayerPayload(p *reversi.Player) protocol.PlayerPayload {
+	return protocol.PlayerPayload{
+		ID:            p.ID().String(),
+		Name:          p.Name(),
+		Token:         p.Token(),
+		Score:         p.Score(),
+		PossibleMoves: p.PossibleMoves(),
+		Level:         p.Level(),
+	}
+}
+
+// broadcastGameState. To broadcast the game state to all clients in the room for render the board data
+func (r *Room) broadcastGameState() {
+	log.Println(r.gameBoard.CurrentPlayer().ID())
+	hash := r.gameBoard.Hash()
+	if round, ok := r.positions[hash]; !ok {
+		r.positions[hash] = r.round
+	} else if round != r.round {
+		log.Printf("room %v round %v: position %016x repeats round %v", r.uuid, r.round, hash, round)
+	}
+
+	var analysis *reversi.Analysis
+	if r.analysis {
+		a := r.gameBoard.Analyze()
+		analysis = &a
+	}
+	m := &protocol.Message{
+		Action: protocol.GameState,
+		Message: protocol.GameStatePayload{
+			P1:            newPlayerPayload(r.gameBoard.P1()),
+			P2:            newPlayerPayload(r.gameBoard.P2()),
+			Players:       r.playerPayloads(),
 			Round:         r.round,
-			Turn:          r.gameBoard.turn,
-			CurrentPlayer: r.gameBoard.CurrentPlayer().id.String(),
-			Board:         r.gameBoard.board,
+			Turn:          r.gameBoard.Turn(),
+			CurrentPlayer: r.gameBoard.CurrentPlayer().ID().String(),
+			Size:          r.gameBoard.Size(),
+			Board:         r.gameBoard.Board(),
+			Hash:          fmt.Sprintf("%016x", hash),
+			Opening:       r.opening,
+			Position:      r.gameBoard.String(),
+			AntiReversi:   r.gameBoard.Objective() == reversi.FewestDiscs,
+			Handicap:      r.handicapPayload(),
+			Analysis:      analysis,
+			Hints:         r.hints,
 		},
 		Target: r.uuid,
 	}
@@ -200,31 +543,34 @@ func (r *Room) broadcastGameState() {
 	r.broadcastToClientsInRoom(m)
 }
 
-func (r *Room) handleMove(c *Client, p Point) {
-	if r.gameBoard.CurrentPlayer().id != c.ID {
+// handleMove plays p for the player with the given ID, a client or the
+// computer player of the room.
+func (r *Room) handleMove(id uuid.UUID, p reversi.Point) {
+	player := r.gameBoard.CurrentPlayer()
+	if player.ID() != id {
 		log.Println("wrong sequence")
 		return
 	}
 
-	flips, err := r.gameBoard.Mark(p, *r.gameBoard.CurrentPlayer())
+	flips, err := r.gameBoard.Mark(p, *player)
 	if err != nil {
-		log.Println("invalid move")
-		m := &Message{
-			Action:  SendMessage,
-			Message: fmt.Sprintf("Invalid move for %v. Try again.", c.name),
+		log.Printf("invalid move %v in %v", p, r.gameBoard)
+		m := &protocol.Message{
+			Action:  protocol.SendMessage,
+			Message: fmt.Sprintf("Invalid move for %v. Try again.", player.Name()),
 			Target:  r.uuid,
 		}
 		r.broadcastToClientsInRoom(m)
 		return
 	}
 
-	m := &Message{
-		Action:  SendMessage,
-		Message: fmt.Sprintf("%v flips %v disks", c.name, flips),
+	m := &protocol.Message{
+		Action:  protocol.SendMessage,
+		Message: fmt.Sprintf("%v flips %v disks", player.Name(), flips),
 		Target:  r.uuid,
 	}
 	r.broadcastToClientsInRoom(m)
-	r.gameBoard.RefreshState()
+	r.updateOpening()
 	r.broadcastGameState()
 
 	if r.gameBoard.EndGame() {
@@ -232,28 +578,91 @@ func (r *Room) handleMove(c *Client, p Point) {
 		return
 	}
 
-	if len(r.gameBoard.CurrentPlayer().possibleMoves) == 0 {
-		m = &Message{
-			Action:  SendMessage,
-			Message: fmt.Sprintf("%v has no possibleMoves and is skipped.", r.gameBoard.CurrentPlayer().name),
+	for r.gameBoard.MustPass() {
+		m = &protocol.Message{
+			Action:  protocol.SendMessage,
+			Message: fmt.Sprintf("%v has no possible moves and passes.", r.gameBoard.CurrentPlayer().Name()),
 			Target:  r.uuid,
 		}
 		r.broadcastToClientsInRoom(m)
+		if err := r.gameBoard.Pass(); err != nil {
+			log.Println(err)
+		}
 		r.broadcastGameState()
 	}
+	r.playComputer()
+}
+
+// playComputer lets the computer player choose its move if it is to move.
+// The search runs on its own goroutine, taking at least r.computerDelay, on a
+// copy of the board so that Run may go on with the room, and the move comes
+// back through Run. The player itself is shared to keep the engines it builds
+// from move to move; only the search changes it meanwhile.
+func (r *Room) playComputer() {
+	if r.gameBoard == nil || r.gameBoard.EndGame() {
+		return
+	}
+	player := r.gameBoard.CurrentPlayer()
+	if player.Type() != reversi.Computer {
+		return
+	}
+	g := r.gameBoard.Clone()
+	m := computerMove{id: player.ID(), round: r.round, turn: g.Turn()}
+	delay := r.computerDelay
+	go func() {
+		start := time.Now()
+		p, err := player.ChooseMove(g)
+		if err != nil {
+			// An external engine is started again after a crash or a
+			// timeout, so it gets a second chance.
+			log.Printf("room %v round %v: computer cannot move: %v", r.uuid, m.round, err)
+			p, err = player.ChooseMove(g)
+		}
+		if err != nil {
+			log.Printf("room %v round %v: computer resigns: %v", r.uuid, m.round, err)
+			m.resign = true
+		}
+		time.Sleep(delay - time.Since(start))
+		m.point = p
+		r.computerMoves <- m
+	}()
+}
 
+// updateOpening names the opening whenever the game reaches the final
+// position of a named line. The last name reached is kept once the game
+// leaves the named lines.
+func (r *Room) updateOpening() {
+	p := r.gameBoard.Position(r.gameBoard.CurrentPlayer().Token())
+	if name, ok := reversi.OpeningName(p); ok {
+		r.opening = name
+	}
 }
 
-// announceWinner. To deduce winner and broadcast to the clients in the room
+// announceWinner. To deduce winner and broadcast to the clients in the room.
+// The game is over from then on, so later moves are turned away.
 func (r *Room) announceWinner() {
+	r.stopEngine()
 	winner := r.gameBoard.Result()
+	if len(r.gameBoard.Players()) > 2 {
+		log.Printf("room %v round %v: %v from %v", r.uuid, r.round, r.gameBoard.Transcript(), r.gameBoard)
+	} else {
+		log.Printf("room %v round %v: %v", r.uuid, r.round, r.gameBoard.GGF(reversi.GameInfo{Place: r.name, Date: time.Now()}))
+	}
 
-	m := &Message{
-		Action: GameResult,
-		Target: r.uuid,
+	result := protocol.GameResultPayload{
+		P1:       newPlayerPayload(r.gameBoard.P1()),
+		P2:       newPlayerPayload(r.gameBoard.P2()),
+		Players:  r.playerPayloads(),
+		Handicap: r.handicapPayload(),
 	}
 	if winner != nil {
-		m.Message = winner.id.String()
+		result.Winner = winner.ID().String()
+	}
+	m := &protocol.Message{
+		Action:  protocol.GameResult,
+		Message: result,
+		Target:  r.uuid,
 	}
 	r.broadcastToClientsInRoom(m)
+	r.gameBoard = nil
 }
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/oscarhkli/reversi"
+	"github.com/oscarhkli/reversi/protocol"
+)
+
+// TestRoomAgainstComputer plays a human against the computer player of a
+// room with scored hints, making moves out of turn as well while the computer
+// searches. Run it with -race to check that the board is only changed by the
+// room.
+func TestRoomAgainstComputer(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	hub := newHub(nil)
+	go hub.run()
+	r := NewRoom("test")
+	go r.Run()
+	c := NewClient(nil, hub, "human", false)
+	c.room = r
+	r.register <- c
+	c.handleStartGameMessage(protocol.StartGamePayload{
+		RoomUUID: r.uuid,
+		Computer: &protocol.ComputerPayload{Level: 1, DelayMs: 1},
+		Hints:    protocol.HintScored,
+	})
+
+	timeout := time.After(time.Minute)
+	for {
+		var b []byte
+		select {
+		case b = <-c.send:
+		case <-timeout:
+			t.Fatal("game not over after a minute")
+		}
+		var msg protocol.Message
+		if err := json.Unmarshal(b, &msg); err != nil {
+			t.Fatal(err)
+		}
+		switch msg.Action {
+		case protocol.GameError:
+			t.Fatalf("game error: %v", msg.Message)
+		case protocol.GameResult:
+			return
+		case protocol.GameState:
+			state, err := unmarshalClientMessagePayload[protocol.GameStatePayload](msg.Message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Out of turn the move is turned away, but it still reaches the
+			// room while the computer is searching.
+			move := reversi.Point{}
+			for _, p := range state.Players {
+				if p.ID != c.ID.String() || len(p.PossibleMoves) == 0 {
+					continue
+				}
+				move = p.PossibleMoves[0]
+				if state.CurrentPlayer == p.ID && len(p.Hints) != len(p.PossibleMoves) {
+					t.Fatalf("hints, want one per possible move, got %v for %v", p.Hints, p.PossibleMoves)
+				}
+			}
+			c.handleMakeMove(protocol.MakeMovePayload{RoomUUID: r.uuid, Point: move})
+		}
+	}
+}
+
+func TestRoomComputerResigns(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	hub := newHub(nil)
+	go hub.run()
+	r := NewRoom("test")
+	go r.Run()
+	c := NewClient(nil, hub, "human", false)
+	c.room = r
+	r.register <- c
+	// The computer thinks long enough for its resignation to come first.
+	c.handleStartGameMessage(protocol.StartGamePayload{
+		RoomUUID: r.uuid,
+		Computer: &protocol.ComputerPayload{Level: 1, DelayMs: 5000},
+	})
+
+	state := nextState(t, c)
+	c.handleMakeMove(protocol.MakeMovePayload{RoomUUID: r.uuid, Point: state.Players[0].PossibleMoves[0]})
+	state = nextState(t, c)
+	if state.CurrentPlayer == c.ID.String() {
+		t.Fatal("want the computer to move")
+	}
+	id, _ := uuid.Parse(state.CurrentPlayer)
+	r.computerMoves <- computerMove{id: id, round: state.Round, turn: state.Turn, resign: true}
+	result, err := unmarshalClientMessagePayload[protocol.GameResultPayload](nextMessage(t, c, protocol.GameResult).Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Winner != c.ID.String() {
+		t.Errorf("winner, want: %v, got %v", c.ID, result.Winner)
+	}
+
+	c.handleMakeMove(protocol.MakeMovePayload{RoomUUID: r.uuid, Point: state.Players[0].PossibleMoves[0]})
+	nextMessage(t, c, protocol.GameError)
+}
+
+// nextState returns the next game state c receives.
+func nextState(t *testing.T, c *Client) protocol.GameStatePayload {
+	t.Helper()
+	state, err := unmarshalClientMessagePayload[protocol.GameStatePayload](nextMessage(t, c, protocol.GameState).Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+// nextMessage returns the next message of the given action c receives,
+// skipping the others. It fails on a game error unless one is wanted.
+func nextMessage(t *testing.T, c *Client, action protocol.MessageType) protocol.Message {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		var b []byte
+		select {
+		case b = <-c.send:
+		case <-timeout:
+			t.Fatalf("no %v message after 10s", action)
+		}
+		var msg protocol.Message
+		if err := json.Unmarshal(b, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Action == action {
+			return msg
+		}
+		if msg.Action == protocol.GameError {
+			t.Fatalf("game error: %v", msg.Message)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/oscarhkli/reversi"
+)
+
+// runSelfPlay plays games of an engine against itself and writes their
+// positions with the final outcomes and search scores as a dataset for
+// train.
+func runSelfPlay(args []string) {
+	fs := flag.NewFlagSet("selfplay", flag.ExitOnError)
+	games := fs.Int("games", 1000, "number of games")
+	depth := fs.Int("depth", 4, "search depth of the engine")
+	eval := fs.String("eval", "", "weights of a pattern evaluation the engine searches with (default the heuristic one)")
+	randomMoves := fs.Int("random", 10, "random moves opening each game")
+	randomRate := fs.Float64("epsilon", 0.05, "chance of a random move after the opening")
+	workers := fs.Int("workers", runtime.NumCPU(), "games played at once")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "seed of the random moves")
+	format := fs.String("format", "bin", "dataset format: bin or csv")
+	out := fs.String("out", "selfplay.bin", "file receiving the dataset")
+	fs.Parse(args)
+
+	search := []reversi.SearchCfgFunc{reversi.WithDepth(*depth)}
+	if *eval != "" {
+		e, err := reversi.LoadPatternEvalFile(*eval)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		search = append(search, reversi.WithEvaluator(e.Evaluate))
+	}
+	dataset := reversi.BinaryDataset
+	switch *format {
+	case "bin":
+	case "csv":
+		dataset = reversi.CSVDataset
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		os.Exit(1)
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	w := reversi.NewSampleWriter(f, dataset)
+
+	start := time.Now()
+	played, samples := 0, 0
+	err = reversi.SelfPlay(*games, func(game []reversi.Sample) error {
+		for _, s := range game {
+			if err := w.Write(s); err != nil {
+				return err
+			}
+		}
+		played++
+		samples += len(game)
+		if played%100 == 0 {
+			fmt.Fprintf(os.Stderr, "%d games, %d positions\n", played, samples)
+		}
+		return nil
+	},
+		reversi.WithSelfPlaySearch(search...),
+		reversi.WithSelfPlayRandomMoves(*randomMoves),
+		reversi.WithSelfPlayRandomRate(*randomRate),
+		reversi.WithSelfPlayWorkers(*workers),
+		reversi.WithSelfPlaySeed(*seed),
+	)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("%d games, %d positions written to %v (%v)\n", played, samples, *out, time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/oscarhkli/reversi"
+)
+
+// runSolve reads a board and prints the best move under perfect play.
+//
+// The board is either the JSON [][]int of GameStatePayload.Board or one row
+// per line with the cells as 0, 1 or 2, e.g. "0 0 1 2 0 0 0 0". A position
+// string given with -position is used instead.
+func runSolve(args []string) {
+	fs := flag.NewFlagSet("solve", flag.ExitOnError)
+	file := fs.String("file", "", "file holding the board (default stdin)")
+	player := fs.Int("player", 1, "token of the player to move")
+	wld := fs.Bool("wld", false, "only solve for win/loss/draw")
+	position := fs.String("position", "", "position string, see GameBoard.String")
+	fs.Parse(args)
+
+	mode := reversi.Exact
+	if *wld {
+		mode = reversi.WLD
+	}
+	start := time.Now()
+	res, err := solve(*file, *position, *player, mode)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if res.Pass {
+		fmt.Println("best move: pass")
+	} else {
+		notation, _ := res.Move.ToNotation()
+		fmt.Println("best move:", notation)
+	}
+	if *wld {
+		fmt.Println("result:", map[int]string{1: "win", 0: "draw", -1: "loss"}[res.Score])
+	} else {
+		fmt.Printf("score: %+d\n", res.Score)
+	}
+	fmt.Printf("nodes: %d (%v)\n", res.Nodes, time.Since(start).Round(time.Millisecond))
+}
+
+// solve solves the game set up by position if it is set, else the board in
+// file or stdin with player to move. Games from a position string are solved
+// by GameBoard.Solve, which turns away the boards the solver cannot play.
+func solve(file, position string, player int, mode reversi.SolveMode) (reversi.SolveResult, error) {
+	if position != "" {
+		g, err := reversi.ParsePosition(position, *reversi.NewPlayer(1), *reversi.NewPlayer(2))
+		if err != nil {
+			return reversi.SolveResult{}, err
+		}
+		return g.Solve(mode)
+	}
+
+	p, err := readPosition(file, player)
+	if err != nil {
+		return reversi.SolveResult{}, err
+	}
+	return reversi.Solve(p, mode)
+}
+
+// readPosition reads the board in file or stdin with player to move.
+func readPosition(file string, player int) (reversi.Position, error) {
+	in := io.Reader(os.Stdin)
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return reversi.Position{}, err
+		}
+		defer f.Close()
+		in = f
+	}
+	board, err := readBoard(in)
+	if err != nil {
+		return reversi.Position{}, err
+	}
+	return reversi.PositionFromBoard(board, player)
+}
+
+func readBoard(r io.Reader) ([][]int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(string(data))
+	if strings.HasPrefix(text, "[") {
+		var board [][]int
+		err := json.Unmarshal([]byte(text), &board)
+		return board, err
+	}
+
+	var board [][]int
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		var row []int
+		for _, c := range scanner.Text() {
+			switch {
+			case c >= '0' && c <= '9':
+				row = append(row, int(c-'0'))
+			case c == ' ' || c == ',' || c == '\t':
+			default:
+				return nil, fmt.Errorf("unexpected character %q in board", c)
+			}
+		}
+		if len(row) > 0 {
+			board = append(board, row)
+		}
+	}
+	if len(board) == 0 {
+		return nil, errors.New("empty board")
+	}
+	return board, scanner.Err()
+}
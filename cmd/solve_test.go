@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oscarhkli/reversi"
+)
+
+func TestSolvePosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		position string
+		err      string
+	}{
+		{"10x10", strings.Repeat("-", 100) + " X", "cannot solve a 10x10 board"},
+		{"blocked", "#" + strings.Repeat("X", 60) + "OO- X", "cannot solve a 8x8 board"},
+		{"endgame", strings.Repeat("X", 60) + "OO-- X", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := solve("", tt.position, 1, reversi.Exact)
+			if tt.err == "" && err != nil {
+				t.Errorf("solve(), want no error, got %v", err)
+			}
+			if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Errorf("solve(), want error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
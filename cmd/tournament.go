@@ -0,0 +1,153 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/oscarhkli/reversi"
+)
+
+// commandsFlag collects the values of a flag given several times.
+type commandsFlag []string
+
+func (c *commandsFlag) String() string {
+	return strings.Join(*c, ", ")
+}
+
+func (c *commandsFlag) Set(s string) error {
+	*c = append(*c, s)
+	return nil
+}
+
+// runTournament plays matches between built-in levels and NBoard engines,
+// then prints the crosstable and the Elo estimates and writes the games as
+// GGF, one per line. The entrants are the levels, each followed by the same
+// level with the pattern evaluation of -eval if given, then the engines, so
+// the first level is the one running a gauntlet.
+func runTournament(args []string) {
+	fs := flag.NewFlagSet("tournament", flag.ExitOnError)
+	levels := fs.String("levels", "1,3,5", "comma-separated built-in levels taking part")
+	var engines commandsFlag
+	fs.Var(&engines, "engine", "command of an NBoard engine taking part, may be repeated")
+	format := fs.String("format", "roundrobin", "pairings: roundrobin or gauntlet")
+	games := fs.Int("games", reversi.DefaultTournamentGames, "games per pairing")
+	openings := fs.Int("openings", 8, "moves of the balanced openings, 0 for the starting position")
+	eval := fs.String("eval", "", "weights of a pattern evaluation each level also plays with")
+	depth := fs.Int("depth", 12, "search depth of the NBoard engines")
+	workers := fs.Int("workers", runtime.NumCPU(), "games played at once")
+	seed := fs.Int64("seed", time.Now().UnixNano(), "seed of the openings")
+	records := fs.String("records", "tournament.ggf", "file receiving the game records")
+	fs.Parse(args)
+
+	var patterns *reversi.PatternEval
+	if *eval != "" {
+		var err error
+		if patterns, err = reversi.LoadPatternEvalFile(*eval); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	var entrants []reversi.Entrant
+	for _, s := range strings.Split(*levels, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		level, err := strconv.Atoi(s)
+		if err != nil || level < reversi.MinLevel || level > reversi.MaxLevel {
+			fmt.Fprintf(os.Stderr, "invalid level %q\n", s)
+			os.Exit(1)
+		}
+		entrants = append(entrants, reversi.Entrant{Name: "level " + s, Level: level})
+		if patterns != nil {
+			entrants = append(entrants, reversi.Entrant{Name: "level " + s + " patterns", Level: level, Eval: patterns.Evaluate})
+		}
+	}
+	for _, command := range engines {
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			continue
+		}
+		entrants = append(entrants, reversi.Entrant{
+			Name: command,
+			NewEngine: func() (reversi.Engine, error) {
+				return reversi.StartNBoardEngine(args[0], args[1:], reversi.WithNBoardDepth(*depth))
+			},
+		})
+	}
+
+	pairings := reversi.RoundRobin
+	switch *format {
+	case "roundrobin":
+	case "gauntlet":
+		pairings = reversi.Gauntlet
+	default:
+		fmt.Fprintf(os.Stderr, "unknown format %q\n", *format)
+		os.Exit(1)
+	}
+
+	out, err := os.Create(*records)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer out.Close()
+
+	played := 0
+	res, err := reversi.RunTournament(entrants,
+		reversi.WithTournamentFormat(pairings),
+		reversi.WithTournamentGames(*games),
+		reversi.WithTournamentOpenings(*openings),
+		reversi.WithTournamentWorkers(*workers),
+		reversi.WithTournamentSeed(*seed),
+		reversi.WithTournamentProgress(func(g reversi.TournamentGame) {
+			played++
+			fmt.Fprintf(os.Stderr, "game %d: %v %d-%d %v\n", played, entrants[g.P1].Name, g.Discs[0], g.Discs[1], entrants[g.P2].Name)
+			if g.Err != nil {
+				fmt.Fprintf(os.Stderr, "game %d: %v\n", played, g.Err)
+			}
+		}),
+	)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, g := range res.Games {
+		if g.GGF != "" {
+			fmt.Fprintln(out, g.GGF)
+		}
+	}
+	printTournament(res)
+}
+
+// printTournament prints the crosstable of res, with the points of each
+// entrant against each opponent, then its total and Elo estimate.
+func printTournament(res reversi.TournamentResult) {
+	points, games := res.Crosstable()
+	ratings := res.Ratings()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "\t\t")
+	for i := range res.Entrants {
+		fmt.Fprintf(w, "%d\t", i+1)
+	}
+	fmt.Fprintln(w, "score\telo\t")
+	for i, e := range res.Entrants {
+		fmt.Fprintf(w, "%d\t%s\t", i+1, e.Name)
+		for j := range res.Entrants {
+			if games[i][j] == 0 {
+				fmt.Fprint(w, "-\t")
+				continue
+			}
+			fmt.Fprintf(w, "%g/%d\t", points[i][j], games[i][j])
+		}
+		r := ratings[i]
+		fmt.Fprintf(w, "%g/%d\t%+.0f ± %.0f\t\n", r.Points, r.Games, r.Elo, r.Margin)
+	}
+	w.Flush()
+}
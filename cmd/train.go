@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/oscarhkli/reversi"
+)
+
+// runTrain fits a pattern evaluation to the datasets of selfplay given as
+// arguments and writes its weights, to be played with -eval.
+func runTrain(args []string) {
+	fs := flag.NewFlagSet("train", flag.ExitOnError)
+	epochs := fs.Int("epochs", 100, "passes over the samples")
+	rate := fs.Float64("rate", 1, "step size of the weights")
+	ridge := fs.Float64("ridge", 100, "penalty on the squared weights")
+	out := fs.String("out", "patterns.bin", "file receiving the weights")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "usage: train [flags] dataset...")
+		os.Exit(2)
+	}
+
+	var samples []reversi.Sample
+	for _, name := range fs.Args() {
+		s, err := reversi.LoadSamplesFile(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", name, err)
+			os.Exit(1)
+		}
+		samples = append(samples, s...)
+	}
+
+	start := time.Now()
+	e, rmse := reversi.TrainPatternEval(samples,
+		reversi.WithTrainEpochs(*epochs),
+		reversi.WithTrainRate(*rate),
+		reversi.WithTrainRidge(*ridge),
+		reversi.WithTrainProgress(func(epoch int, rmse float64) {
+			if epoch%10 == 0 {
+				fmt.Fprintf(os.Stderr, "epoch %d: rmse %.3f\n", epoch, rmse)
+			}
+		}),
+	)
+
+	f, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	if _, err := e.WriteTo(f); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("%d samples, rmse %.3f discs, weights written to %v (%v)\n", len(samples), rmse, *out, time.Since(start).Round(time.Millisecond))
+}
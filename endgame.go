@@ -0,0 +1,230 @@
+package reversi
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// MaxSolveEmpties is the largest number of empty cells Solve accepts.
+const MaxSolveEmpties = 20
+
+const (
+	// fastestFirstEmpties is the number of empties from which moves are
+	// ordered by the opponent's mobility and the transposition table is used.
+	// Below it they cost more than they save.
+	fastestFirstEmpties = 7
+
+	// solverSalt keeps the exact disc differentials of the solver apart from
+	// the heuristic scores of Searcher when both share a table.
+	solverSalt uint64 = 0x9d1f5c3a7e2b4680
+)
+
+type SolveMode int
+
+const (
+	// Exact finds the final disc differential under perfect play.
+	Exact SolveMode = iota
+	// WLD only finds whether the game is won, lost or drawn, which is faster.
+	WLD
+)
+
+type SolveCfg struct {
+	tt        *TranspositionTable
+	objective Objective
+}
+
+type SolveCfgFunc func(*SolveCfg)
+
+// WithSolverTable makes Solve use tt, which may be shared with other engines.
+func WithSolverTable(tt *TranspositionTable) SolveCfgFunc {
+	return func(cfg *SolveCfg) {
+		cfg.tt = tt
+	}
+}
+
+// WithSolverObjective makes Solve play for objective. Under FewestDiscs the
+// score is the disc differential of the opponent.
+func WithSolverObjective(objective Objective) SolveCfgFunc {
+	return func(cfg *SolveCfg) {
+		cfg.objective = objective
+	}
+}
+
+// SolveResult is the outcome of Solve. Score is from the side to move: the
+// final disc differential in Exact mode, or 1, 0, -1 for a win, draw or loss
+// in WLD mode. Pass is set when the side to move has no legal move.
+type SolveResult struct {
+	Move  Point
+	Pass  bool
+	Score int
+	Nodes int
+}
+
+// Solve plays out p perfectly to the end of the game. Passes are part of the
+// search, so p may be a position where the side to move cannot play.
+func Solve(p Position, mode SolveMode, cfgFuncs ...SolveCfgFunc) (SolveResult, error) {
+	var cfg SolveCfg
+	for _, cfgFunc := range cfgFuncs {
+		cfgFunc(&cfg)
+	}
+	if cfg.tt == nil {
+		cfg.tt = NewTranspositionTable(defaultTableSize)
+	}
+	cfg.tt.NewSearch()
+
+	if e := p.Empties(); e > MaxSolveEmpties {
+		return SolveResult{}, fmt.Errorf("too many empties to solve: %d > %d", e, MaxSolveEmpties)
+	}
+
+	alpha, beta := -Width*Height-1, Width*Height+1
+	if mode == WLD {
+		alpha, beta = -1, 1
+	}
+
+	s := solver{tt: cfg.tt, sign: cfg.objective.sign(), salt: solverSalt}
+	if cfg.objective == FewestDiscs {
+		s.salt ^= fewestDiscsSalt
+	}
+	res := SolveResult{}
+	moves := p.Moves()
+	if moves == 0 {
+		if p.Pass().Moves() == 0 {
+			return SolveResult{}, errors.New("game is over")
+		}
+		res.Pass = true
+		res.Score = -s.solve(p.Pass(), -beta, -alpha)
+	} else {
+		res.Score = alpha
+		for i, sq := range s.order(p, moves, -1) {
+			score := -s.solve(p.play(sq), -beta, -max(alpha, res.Score))
+			if i == 0 || score > res.Score {
+				res.Move, res.Score = squarePoint(sq), score
+			}
+			if res.Score >= beta {
+				break
+			}
+		}
+	}
+
+	if mode == WLD {
+		res.Score = sign(res.Score)
+	}
+	res.Nodes = s.nodes
+	return res, nil
+}
+
+// Solve solves the game from the player to move under the objective of g.
+func (g GameBoard) Solve(mode SolveMode, cfgFuncs ...SolveCfgFunc) (SolveResult, error) {
+	if !g.standard() {
+		return SolveResult{}, fmt.Errorf("cannot solve a %dx%d board", g.Size(), g.Size())
+	}
+	cfgFuncs = append([]SolveCfgFunc{WithSolverObjective(g.cfg.objective)}, cfgFuncs...)
+	return Solve(g.Position(g.CurrentPlayer().token), mode, cfgFuncs...)
+}
+
+type solver struct {
+	tt    *TranspositionTable
+	nodes int
+	// sign is the Objective.sign of the game and salt the key of its scores
+	// in the table.
+	sign int
+	salt uint64
+}
+
+func (s *solver) solve(p Position, alpha, beta int) int {
+	s.nodes++
+	moves := p.Moves()
+	if moves == 0 {
+		if p.Pass().Moves() == 0 {
+			return s.sign * p.DiscDiff()
+		}
+		return -s.solve(p.Pass(), -beta, -alpha)
+	}
+
+	if p.Empties() < fastestFirstEmpties {
+		for ; moves != 0; moves &= moves - 1 {
+			score := -s.solve(p.play(bits.TrailingZeros64(moves)), -beta, -alpha)
+			if score >= beta {
+				return score
+			}
+			if score > alpha {
+				alpha = score
+			}
+		}
+		return alpha
+	}
+
+	key := p.Hash() ^ s.salt
+	ttMove := -1
+	if e, ok := s.tt.Probe(key); ok {
+		ttMove = e.Move
+		switch e.Bound {
+		case ExactBound:
+			return e.Score
+		case LowerBound:
+			alpha = max(alpha, e.Score)
+		case UpperBound:
+			beta = min(beta, e.Score)
+		}
+		if alpha >= beta {
+			return e.Score
+		}
+	}
+
+	alphaOrig := alpha
+	best, bestMove := -Width*Height-1, -1
+	for _, sq := range s.order(p, moves, ttMove) {
+		score := -s.solve(p.play(sq), -beta, -alpha)
+		if score > best {
+			best, bestMove = score, sq
+		}
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	bound := ExactBound
+	if best <= alphaOrig {
+		bound = UpperBound
+	} else if best >= beta {
+		bound = LowerBound
+	}
+	s.tt.Store(key, TTEntry{Depth: p.Empties(), Score: best, Bound: bound, Move: bestMove})
+	return best
+}
+
+// order sorts the moves so the ones leaving the opponent the fewest replies
+// come first, which cuts the tree the most near the end of the game. The move
+// first, if present, is put in front.
+func (s *solver) order(p Position, moves uint64, first int) []int {
+	res := make([]int, 0, bits.OnesCount64(moves))
+	mobility := make([]int, 0, cap(res))
+	for ; moves != 0; moves &= moves - 1 {
+		sq := bits.TrailingZeros64(moves)
+		m := bits.OnesCount64(p.play(sq).Moves())
+		if sq == first {
+			m = -1
+		}
+		i := len(res)
+		res, mobility = append(res, sq), append(mobility, m)
+		for ; i > 0 && mobility[i-1] > m; i-- {
+			res[i], mobility[i] = res[i-1], mobility[i-1]
+		}
+		res[i], mobility[i] = sq, m
+	}
+	return res
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
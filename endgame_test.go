@@ -0,0 +1,125 @@
+package reversi
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// minimax is a plain full-width search used as the reference for Solve.
+func minimax(p Position) int {
+	return minimaxFor(p, MostDiscs)
+}
+
+func minimaxFor(p Position, objective Objective) int {
+	moves := p.Moves()
+	if moves == 0 {
+		if p.Pass().Moves() == 0 {
+			return objective.sign() * p.DiscDiff()
+		}
+		return -minimaxFor(p.Pass(), objective)
+	}
+	best := -Width*Height - 1
+	for _, sq := range orderMoves(moves, -1) {
+		best = max(best, -minimaxFor(p.play(sq), objective))
+	}
+	return best
+}
+
+// randomPosition plays random moves from the start until empties cells are
+// left and the side to move is not stuck at the end of the game.
+func randomPosition(seed int64, empties int) Position {
+	rng := rand.New(rand.NewSource(seed))
+	for {
+		g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+		p := g.Position(1)
+		for p.Empties() > empties && !p.GameOver() {
+			moves := orderMoves(p.Moves(), -1)
+			if len(moves) == 0 {
+				p = p.Pass()
+				continue
+			}
+			p = p.play(moves[rng.Intn(len(moves))])
+		}
+		if !p.GameOver() {
+			return p
+		}
+	}
+}
+
+func TestSolve(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		p := randomPosition(seed, 9)
+		want := minimax(p)
+
+		exact, err := Solve(p, Exact)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exact.Score != want {
+			t.Errorf("seed %v: Solve(Exact).Score, want: %v, got %v", seed, want, exact.Score)
+		}
+		if !exact.Pass {
+			if got := -minimax(p.Play(exact.Move)); got != want {
+				t.Errorf("seed %v: Solve(Exact).Move %v scores %v, want: %v", seed, exact.Move, got, want)
+			}
+		}
+
+		wld, err := Solve(p, WLD)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := wld.Score, sign(want); got != want {
+			t.Errorf("seed %v: Solve(WLD).Score, want: %v, got %v", seed, want, got)
+		}
+	}
+}
+
+func TestSolveFewestDiscs(t *testing.T) {
+	tt := NewTranspositionTable(1 << 12)
+	for seed := int64(0); seed < 5; seed++ {
+		p := randomPosition(seed, 9)
+		// Both objectives share tt to check that their scores are kept apart.
+		if _, err := Solve(p, Exact, WithSolverTable(tt)); err != nil {
+			t.Fatal(err)
+		}
+		res, err := Solve(p, Exact, WithSolverTable(tt), WithSolverObjective(FewestDiscs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := minimaxFor(p, FewestDiscs); res.Score != want {
+			t.Errorf("seed %v: Solve(FewestDiscs).Score, want: %v, got %v", seed, want, res.Score)
+		}
+	}
+}
+
+func TestSolvePass(t *testing.T) {
+	// White cannot move, black then takes g8 and leaves white without discs.
+	p := positionFromGrid(t, [][]int{
+		{0, 1, 1, 1, 1, 1, 1, 1},
+		{1, 1, 1, 1, 1, 1, 1, 1},
+		{1, 1, 1, 1, 1, 1, 1, 1},
+		{1, 1, 1, 1, 1, 1, 1, 1},
+		{1, 1, 1, 1, 1, 1, 1, 1},
+		{1, 1, 1, 1, 1, 1, 1, 1},
+		{1, 1, 1, 1, 1, 1, 1, 1},
+		{1, 1, 1, 1, 1, 1, 2, 0},
+	}, 2)
+
+	res, err := Solve(p, Exact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Pass {
+		t.Errorf("Solve().Pass, want: true, got %v", res)
+	}
+	if got, want := res.Score, -63; got != want {
+		t.Errorf("Solve().Score, want: %v, got %v", want, got)
+	}
+}
+
+func TestSolveTooManyEmpties(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	if _, err := g.Solve(Exact); err == nil {
+		t.Errorf("Solve(start), want error, got nil")
+	}
+}
@@ -0,0 +1,86 @@
+package reversi
+
+import "math/bits"
+
+// Evaluator scores a position from the side to move. Higher is better.
+type Evaluator func(p Position) int
+
+// EvalWeights are the factors of the heuristic evaluation.
+type EvalWeights struct {
+	// Mobility rewards each legal move more than the opponent has.
+	Mobility int
+	// Corners rewards each corner more than the opponent owns.
+	Corners int
+	// XSquares penalises each disc diagonally next to an empty corner.
+	XSquares int
+	// CSquares penalises each disc on an edge next to an empty corner.
+	CSquares int
+	// Parity rewards having the last move in the game.
+	Parity int
+}
+
+var DefaultEvalWeights = EvalWeights{
+	Mobility: 10,
+	Corners:  100,
+	XSquares: 50,
+	CSquares: 20,
+	Parity:   5,
+}
+
+const (
+	cornerMask uint64 = 1<<0 | 1<<7 | 1<<56 | 1<<63
+)
+
+// cornerRegions lists each corner with its X-square and C-squares.
+var cornerRegions = [4]struct {
+	corner  uint64
+	xSquare uint64
+	cSquare uint64
+}{
+	{1 << 0, 1 << 9, 1<<1 | 1<<8},     // a1: b2; b1, a2
+	{1 << 7, 1 << 14, 1<<6 | 1<<15},   // h1: g2; g1, h2
+	{1 << 56, 1 << 49, 1<<48 | 1<<57}, // a8: b7; a7, b8
+	{1 << 63, 1 << 54, 1<<55 | 1<<62}, // h8: g7; h7, g8
+}
+
+// AntiEval builds an Evaluator for FewestDiscs games from the same weights as
+// HeuristicEval. Mobility still helps, but corners and the discs that cannot
+// be flipped back are now a burden, so the other terms change sign and every
+// disc more than the opponent costs a point.
+func AntiEval(w EvalWeights) Evaluator {
+	eval := HeuristicEval(EvalWeights{
+		Mobility: w.Mobility,
+		Corners:  -w.Corners,
+		XSquares: -w.XSquares,
+		CSquares: -w.CSquares,
+		Parity:   -w.Parity,
+	})
+	return func(p Position) int {
+		return eval(p) - p.DiscDiff()
+	}
+}
+
+// HeuristicEval builds an Evaluator from mobility, corners, X/C-squares and
+// disc parity.
+func HeuristicEval(w EvalWeights) Evaluator {
+	return func(p Position) int {
+		score := w.Mobility * (bits.OnesCount64(p.Moves()) - bits.OnesCount64(p.Pass().Moves()))
+		score += w.Corners * (bits.OnesCount64(p.Own&cornerMask) - bits.OnesCount64(p.Opp&cornerMask))
+
+		empty := ^(p.Own | p.Opp)
+		for _, r := range cornerRegions {
+			if r.corner&empty == 0 {
+				continue
+			}
+			score -= w.XSquares * (bits.OnesCount64(p.Own&r.xSquare) - bits.OnesCount64(p.Opp&r.xSquare))
+			score -= w.CSquares * (bits.OnesCount64(p.Own&r.cSquare) - bits.OnesCount64(p.Opp&r.cSquare))
+		}
+
+		if p.Empties()%2 == 1 {
+			score += w.Parity
+		} else {
+			score -= w.Parity
+		}
+		return score
+	}
+}
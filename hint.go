@@ -0,0 +1,41 @@
+package reversi
+
+import "sort"
+
+// hintTableSize is the number of slots of the transposition table of the
+// searches behind Hints. Hint searches are shallow, so it is kept small.
+const hintTableSize = 1 << 12
+
+// Hint is a legal move of the player to move with its score, higher being
+// better. Best marks the moves scoring as high as any other.
+type Hint struct {
+	Point Point `json:"point"`
+	Score int   `json:"score"`
+	Best  bool  `json:"best"`
+}
+
+// Hints scores the legal moves of the player to move, best first. On the
+// standard board the score comes from a search of depth plies under the
+// objective of the game, in the units of the Evaluator. On other boards, where
+// the engines cannot play, it is the score of the greedy Computer player.
+func (g GameBoard) Hints(depth int) []Hint {
+	p := g.CurrentPlayer()
+	var res []Hint
+	if g.standard() {
+		s := NewSearcher(WithDepth(depth), WithSearchObjective(g.cfg.objective), WithTranspositionTable(NewTranspositionTable(hintTableSize)))
+		for _, ms := range s.ScoreMoves(g.Position(p.token)) {
+			res = append(res, Hint{Point: ms.Move, Score: ms.Score})
+		}
+	} else {
+		for _, point := range p.PossibleMoves() {
+			res = append(res, Hint{Point: point, Score: greedyScore(point, len(p.possibleMoves[point]), g.cfg.size, g.cfg.objective)})
+		}
+		sort.SliceStable(res, func(i, j int) bool {
+			return res[i].Score > res[j].Score
+		})
+	}
+	for i := range res {
+		res[i].Best = res[i].Score == res[0].Score
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package reversi
+
+import "testing"
+
+func TestHints(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	hints := g.Hints(3)
+	if len(hints) != 4 {
+		t.Fatalf("len(Hints()), want: 4, got %v", len(hints))
+	}
+	for _, h := range hints {
+		if !h.Best || !g.P1().CanMove(h.Point) {
+			t.Errorf("Hints() of the symmetric start, want every move best, got %+v", h)
+		}
+	}
+
+	g = mustParsePosition(t, `
+		-OOX----
+		--------
+		--------
+		---XO---
+		---OX---
+		--------
+		--------
+		--------
+		X`)
+	hints = g.Hints(2)
+	if got, want := hints[0], (Hint{Point: Point{0, 0}, Score: hints[0].Score, Best: true}); got != want {
+		t.Errorf("Hints()[0], want: %+v, got %+v", want, got)
+	}
+	for i := 1; i < len(hints); i++ {
+		if hints[i].Best || hints[i].Score > hints[i-1].Score {
+			t.Errorf("Hints(), want the corner alone best and the rest in order, got %+v", hints)
+		}
+	}
+
+	g = NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithSize(10), WithObjective(FewestDiscs))
+	if got, want := len(g.Hints(3)), len(g.P1().PossibleMoves()); got != want {
+		t.Errorf("len(Hints()) on 10x10, want: %v, got %v", want, got)
+	}
+}
+
+func TestScoreMoves(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		p := randomPosition(seed, 7)
+		scores := NewSearcher(WithDepth(20)).ScoreMoves(p)
+		if len(scores) == 0 {
+			continue
+		}
+		if got, want := -minimax(p.Play(scores[0].Move)), minimax(p); got != want {
+			t.Errorf("seed %v: ScoreMoves()[0] %v scores %v, want: %v", seed, scores[0].Move, got, want)
+		}
+		for i := 1; i < len(scores); i++ {
+			if scores[i].Score > scores[i-1].Score {
+				t.Errorf("seed %v: ScoreMoves() not sorted, got %v", seed, scores)
+			}
+			if diff := -minimax(p.Play(scores[i].Move)); diff > -minimax(p.Play(scores[i-1].Move)) {
+				t.Errorf("seed %v: ScoreMoves() puts %v before %v", seed, scores[i-1].Move, scores[i].Move)
+			}
+		}
+	}
+}
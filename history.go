@@ -0,0 +1,126 @@
+package reversi
+
+import "errors"
+
+// Move is one turn of a game: a disc placed at Point by the player holding
+// Token, or a pass. Turn is the turn counter before the move. In games of
+// more than two players Owners holds the token each of Flips belonged to;
+// with two it is always the opponent.
+type Move struct {
+	Point  Point   `json:"point"`
+	Token  int     `json:"token"`
+	Flips  []Point `json:"flips"`
+	Owners []int   `json:"owners,omitempty"`
+	Pass   bool    `json:"pass"`
+	Turn   int     `json:"turn"`
+}
+
+// History returns the moves played so far, oldest first.
+func (g GameBoard) History() []Move {
+	return append([]Move(nil), g.history...)
+}
+
+// playedMoves returns the number of discs placed so far, passes aside.
+func (g GameBoard) playedMoves() int {
+	n := 0
+	for _, m := range g.history {
+		if !m.Pass {
+			n++
+		}
+	}
+	return n
+}
+
+// CanUndo reports whether there is a move to take back.
+func (g GameBoard) CanUndo() bool {
+	return len(g.history) > 0
+}
+
+// CanRedo reports whether there is a taken back move to play again.
+func (g GameBoard) CanRedo() bool {
+	return len(g.redo) > 0
+}
+
+// Undo takes back the last move, restoring the board, scores, possible moves
+// and turn as they were before it.
+func (g *GameBoard) Undo() error {
+	if !g.CanUndo() {
+		return errors.New("nothing to undo")
+	}
+	m := g.history[len(g.history)-1]
+	g.history = g.history[:len(g.history)-1]
+	g.redo = append(g.redo, m)
+
+	if !m.Pass {
+		g.unplay(m)
+	}
+	g.setToMove(m.Token)
+	g.turn = m.Turn
+	g.refreshPlayers()
+	return nil
+}
+
+// Redo plays the last taken back move again.
+func (g *GameBoard) Redo() error {
+	if !g.CanRedo() {
+		return errors.New("nothing to redo")
+	}
+	m := g.redo[len(g.redo)-1]
+	g.redo = g.redo[:len(g.redo)-1]
+	g.history = append(g.history, m)
+
+	if !m.Pass {
+		g.replay(m)
+	}
+	g.setToMove(g.next(m.Token))
+	g.turn = m.Turn + 1
+	g.refreshPlayers()
+	return nil
+}
+
+// record adds m to the history. A new move drops the moves that were taken
+// back.
+func (g *GameBoard) record(m Move) {
+	g.history = append(g.history, m)
+	g.redo = nil
+}
+
+func (g *GameBoard) replay(m Move) {
+	if g.standard() {
+		f := pointsMask(m.Flips)
+		g.bb.discs[m.Token-1] |= f | squareBit(m.Point)
+		g.bb.discs[2-m.Token] &^= f
+	}
+	g.hash ^= g.cellKey(m.Token, m.Point)
+	g.board[m.Point.Y][m.Point.X] = m.Token
+	for _, p := range m.Flips {
+		g.hash ^= g.cellKey(m.Token, p) ^ g.cellKey(g.board[p.Y][p.X], p)
+		g.board[p.Y][p.X] = m.Token
+	}
+}
+
+func (g *GameBoard) unplay(m Move) {
+	if g.standard() {
+		f := pointsMask(m.Flips)
+		g.bb.discs[m.Token-1] &^= f | squareBit(m.Point)
+		g.bb.discs[2-m.Token] |= f
+	}
+	g.hash ^= g.cellKey(m.Token, m.Point)
+	g.board[m.Point.Y][m.Point.X] = 0
+	for i, p := range m.Flips {
+		owner := 3 - m.Token
+		if m.Owners != nil {
+			owner = m.Owners[i]
+		}
+		g.hash ^= g.cellKey(m.Token, p) ^ g.cellKey(owner, p)
+		g.board[p.Y][p.X] = owner
+	}
+}
+
+func pointsMask(points []Point) uint64 {
+	var mask uint64
+	for _, p := range points {
+		mask |= squareBit(p)
+	}
+	return mask
+}
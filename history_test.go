@@ -0,0 +1,163 @@
+package reversi
+
+import (
+	"reflect"
+	"testing"
+)
+
+// snapshot is the state Undo and Redo have to restore.
+type snapshot struct {
+	board            [][]int
+	bb               bitboard
+	hash             uint64
+	turn             int
+	scores           [2]int
+	p1Moves, p2Moves []Point
+}
+
+func takeSnapshot(g *GameBoard) snapshot {
+	return snapshot{
+		board:   g.Board(),
+		bb:      g.bb,
+		hash:    g.Hash(),
+		turn:    g.turn,
+		scores:  [2]int{g.P1().score, g.P2().score},
+		p1Moves: g.P1().PossibleMoves(),
+		p2Moves: g.P2().PossibleMoves(),
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+		snapshots := []snapshot{takeSnapshot(g)}
+		playRandomGame(seed, func(h *GameBoard) {
+			if h.turn > 1 {
+				snapshots = append(snapshots, takeSnapshot(h))
+			}
+			g = h
+		})
+
+		if got, want := len(g.History()), len(snapshots)-1; got != want {
+			t.Fatalf("seed %v: len(History()), want: %v, got %v", seed, want, got)
+		}
+		for i := len(snapshots) - 2; i >= 0; i-- {
+			if err := g.Undo(); err != nil {
+				t.Fatal(err)
+			}
+			if got := takeSnapshot(g); !reflect.DeepEqual(got, snapshots[i]) {
+				t.Fatalf("seed %v: Undo() to turn %v, want: %v, got %v", seed, i+1, snapshots[i], got)
+			}
+		}
+		if err := g.Undo(); err == nil {
+			t.Errorf("seed %v: Undo() at the start, want error, got nil", seed)
+		}
+
+		for i := 1; i < len(snapshots); i++ {
+			if err := g.Redo(); err != nil {
+				t.Fatal(err)
+			}
+			if got := takeSnapshot(g); !reflect.DeepEqual(got, snapshots[i]) {
+				t.Fatalf("seed %v: Redo() to turn %v, want: %v, got %v", seed, i+1, snapshots[i], got)
+			}
+		}
+		if err := g.Redo(); err == nil {
+			t.Errorf("seed %v: Redo() at the end, want error, got nil", seed)
+		}
+	}
+}
+
+func TestHistoryRecordsPasses(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	g.Mark(Point{4, 2}, *g.P1())
+	setBoard(g, [][]int{
+		{1, 2, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+	}, 2)
+	if err := g.Pass(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Move{
+		{Point: Point{4, 2}, Token: 1, Flips: []Point{{4, 3}}, Turn: 1},
+		{Token: 2, Pass: true, Turn: 2},
+	}
+	if got := g.History(); !reflect.DeepEqual(got, want) {
+		t.Errorf("History(), want: %v, got %v", want, got)
+	}
+	if got, want := g.CurrentPlayer(), g.P1(); got != want {
+		t.Errorf("CurrentPlayer() after the pass, want: %v, got %v", want.name, got.name)
+	}
+}
+
+func TestPass(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	if err := g.Pass(); err == nil {
+		t.Errorf("Pass() with possible moves, want error, got nil")
+	}
+	if _, err := g.Mark(Point{4, 2}, *g.P2()); err == nil {
+		t.Errorf("Mark() out of turn, want error, got nil")
+	}
+
+	setBoard(g, [][]int{
+		{1, 1, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+	}, 2)
+	if !g.EndGame() || g.MustPass() {
+		t.Errorf("EndGame(), MustPass(), want: (true, false), got (%v, %v)", g.EndGame(), g.MustPass())
+	}
+	if err := g.Pass(); err == nil {
+		t.Errorf("Pass() after the end of the game, want error, got nil")
+	}
+}
+
+func TestP2First(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithP1First(false))
+	for i := 0; i < 4; i++ {
+		want := g.P2()
+		if i%2 == 1 {
+			want = g.P1()
+		}
+		p := g.CurrentPlayer()
+		if p != want {
+			t.Fatalf("CurrentPlayer() at move %v, want: %v, got %v", i+1, want.name, p.name)
+		}
+		if _, err := g.Mark(p.PossibleMoves()[0], *p); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// setBoard replaces the discs of g and gives the turn to toMove.
+func setBoard(g *GameBoard, board [][]int, toMove int) {
+	g.board = board
+	g.bb = newBitboard(board)
+	g.toMove = toMove
+	g.hash = zobristMask(1, g.bb.own(1)) ^ zobristMask(2, g.bb.own(2))
+	if toMove == 2 {
+		g.hash ^= zobristSide
+	}
+	g.refreshPlayers()
+}
+
+func TestMarkClearsRedo(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	g.Mark(Point{4, 2}, *g.P1())
+	g.Undo()
+	g.Mark(Point{2, 4}, *g.P1())
+	if g.CanRedo() {
+		t.Errorf("CanRedo() after a new move, want false")
+	}
+}
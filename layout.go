@@ -0,0 +1,151 @@
+package reversi
+
+import "math/rand"
+
+// Blocked is the value of a blocked cell on the board. Blocked cells cannot
+// be played on and lines of discs do not run through them.
+const Blocked = -1
+
+const (
+	// balancedMargin is the largest evaluation, in Evaluator units, a random
+	// opening may leave to either side to count as balanced.
+	balancedMargin = 20
+	// balancedTries bounds the openings drawn by RandomBalancedLayout.
+	balancedTries = 100
+)
+
+// MaxHandicap is the largest number of corners a handicap can give.
+const MaxHandicap = 4
+
+// Handicap gives the player holding Token a disc on Corners corners before
+// the first move. The zero value is no handicap.
+type Handicap struct {
+	Token   int `json:"token"`
+	Corners int `json:"corners"`
+}
+
+func (h Handicap) valid() bool {
+	return h == Handicap{} || (h.Token == 1 || h.Token == 2) && h.Corners >= 1 && h.Corners <= MaxHandicap
+}
+
+// WithHandicap gives the player holding token a disc on 1 to MaxHandicap
+// corners, taken in the order a1, h8, h1, a8. Handicap discs count like any
+// other disc. Corners that are blocked or already taken are skipped, and
+// invalid handicaps are ignored. Setting up a game from a position string
+// only records the handicap, as the discs are part of the position.
+func WithHandicap(token, corners int) GameCfgFunc {
+	return func(cfg *GameCfg) {
+		cfg.handicap = Handicap{Token: token, Corners: corners}
+	}
+}
+
+// handicapCorners returns the corners of a board of the given size in the
+// order they are handed out.
+func handicapCorners(size int) []Point {
+	return []Point{{0, 0}, {size - 1, size - 1}, {size - 1, 0}, {0, size - 1}}
+}
+
+// WithBlocked blocks the given cells. Discs on them are removed and cells off
+// the board are ignored.
+func WithBlocked(points ...Point) GameCfgFunc {
+	return func(cfg *GameCfg) {
+		cfg.blocked = append(cfg.blocked, points...)
+	}
+}
+
+// WithLayout starts the game from board, indexed [y][x] with 0, 1, 2 or
+// Blocked, instead of the four discs in the centre. The board size follows
+// the layout. Layouts that are not square or not of a valid size are ignored.
+func WithLayout(board [][]int) GameCfgFunc {
+	return func(cfg *GameCfg) {
+		cfg.layout = board
+	}
+}
+
+// validLayout reports whether board can be played on.
+func validLayout(board [][]int) bool {
+	if !validSize(len(board)) {
+		return false
+	}
+	for _, row := range board {
+		if len(row) != len(board) {
+			return false
+		}
+		for _, cell := range row {
+			if cell < Blocked || cell > 2 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// RandomBalancedLayout plays moves random moves from the starting position
+// of the standard board and returns the resulting board, like the openings
+// drawn for engine tournaments. Openings are drawn until a short search finds
+// them close to even; the most even one is kept if none is. The first player
+// is to move when moves is even.
+func RandomBalancedLayout(rng *rand.Rand, moves int) [][]int {
+	s := NewSearcher(WithDepth(4))
+	var best [][]int
+	bestScore := 0
+	for i := 0; i < balancedTries; i++ {
+		g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+		for j := 0; j < moves && !g.EndGame(); j++ {
+			if g.MustPass() {
+				g.Pass()
+				continue
+			}
+			p := g.CurrentPlayer()
+			choices := p.PossibleMoves()
+			g.Mark(choices[rng.Intn(len(choices))], *p)
+		}
+		if g.EndGame() || g.MustPass() {
+			continue
+		}
+
+		score := s.Search(g.Position(g.CurrentPlayer().token)).Score
+		if score < 0 {
+			score = -score
+		}
+		if best == nil || score < bestScore {
+			best, bestScore = g.Board(), score
+		}
+		if score <= balancedMargin {
+			break
+		}
+	}
+	if best == nil {
+		return NewGameBoard(*NewPlayer(1), *NewPlayer(2)).Board()
+	}
+	return best
+}
+
+// RandomObstacles picks n empty cells of the starting position on a board of
+// the given size to block. The cells come in pairs mirrored through the
+// centre so that neither side is favoured; an odd n is rounded down.
+func RandomObstacles(rng *rand.Rand, size, n int) []Point {
+	if !validSize(size) {
+		size = Width
+	}
+	c := size / 2
+	var free []Point
+	// Only the upper half is drawn from; each cell brings its mirror image.
+	for y := 0; y < c; y++ {
+		for x := 0; x < size; x++ {
+			if y == c-1 && (x == c-1 || x == c) {
+				continue
+			}
+			free = append(free, Point{x, y})
+		}
+	}
+	rng.Shuffle(len(free), func(i, j int) {
+		free[i], free[j] = free[j], free[i]
+	})
+
+	res := make([]Point, 0, n)
+	for _, p := range free[:min(n/2, len(free))] {
+		res = append(res, p, Point{size - 1 - p.X, size - 1 - p.Y})
+	}
+	return res
+}
@@ -0,0 +1,154 @@
+package reversi
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestBlockedCellsAreWalls(t *testing.T) {
+	g := mustParsePosition(t, `
+		X#O-----
+		--------
+		--O-----
+		--------
+		X#O-----
+		-#------
+		--------
+		--------
+		X`)
+	if got, want := g.PossibleMoves(1), map[Point][]Point{}; !equalMapUnorderedSlice(got, want) {
+		t.Errorf("PossibleMoves(1), want: %v, got %v", want, got)
+	}
+	if got := g.Cell(Point{1, 0}); got != Blocked {
+		t.Errorf("Cell(b1), want: %v, got %v", Blocked, got)
+	}
+	if got, want := g.String(), "X#O---------------O-------------X#O------#---------------------- X"; got != want {
+		t.Errorf("String(), want: %v, got %v", want, got)
+	}
+}
+
+func TestWithBlocked(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1, WithPlayerType(Computer)), *NewPlayer(2), WithBlocked(Point{4, 2}, Point{9, 9}))
+	if g.P1().CanMove(Point{4, 2}) {
+		t.Errorf("CanMove(e3) on a blocked cell, want false")
+	}
+	if got, want := len(g.P1().PossibleMoves()), 3; got != want {
+		t.Errorf("len(PossibleMoves()), want: %v, got %v", want, got)
+	}
+	if p, err := g.P1().ChooseMove(g); err != nil || !g.P1().CanMove(p) {
+		t.Errorf("ChooseMove() with blocked cells, want a legal move, got (%v, %v)", p, err)
+	}
+
+	for seed := int64(0); seed < 5; seed++ {
+		blocked := RandomObstacles(rand.New(rand.NewSource(seed)), 8, 6)
+		playRandomGameWith(seed, func(g *GameBoard) {
+			for _, p := range blocked {
+				if g.Cell(p) != Blocked {
+					t.Fatalf("seed %v: Cell(%v), want blocked, got %v", seed, p, g.Cell(p))
+				}
+			}
+			if g.P1().Score()+g.P2().Score() != 4+len(g.History())-passes(g) {
+				t.Fatalf("seed %v: scores out of sync", seed)
+			}
+		}, WithBlocked(blocked...))
+	}
+}
+
+func TestWithLayout(t *testing.T) {
+	layout := [][]int{
+		{0, 0, 0, 0, 0, 0},
+		{0, 1, 0, 0, 2, 0},
+		{0, 0, 1, 2, 0, 0},
+		{0, 0, 2, 1, 0, 0},
+		{0, 2, 0, Blocked, 1, 0},
+		{0, 0, 0, 0, 0, 0},
+	}
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithLayout(layout), WithP1First(false))
+	if g.Size() != 6 || g.Cell(Point{3, 4}) != Blocked || g.P1().Score() != 4 || g.P2().Score() != 4 {
+		t.Errorf("WithLayout(), got %v", g)
+	}
+	if got, want := g.CurrentPlayer().Token(), 2; got != want {
+		t.Errorf("CurrentPlayer(), want: %v, got %v", want, got)
+	}
+
+	g = NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithLayout([][]int{{0, 1}, {2, 0}}))
+	if got, want := g.String(), NewGameBoard(*NewPlayer(1), *NewPlayer(2)).String(); got != want {
+		t.Errorf("WithLayout() of an invalid layout, want: %v, got %v", want, got)
+	}
+}
+
+func TestRandomBalancedLayout(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 3; i++ {
+		layout := RandomBalancedLayout(rng, 8)
+		g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithLayout(layout))
+		if got, want := g.P1().Score()+g.P2().Score(), 12; got != want {
+			t.Errorf("discs after 8 random moves, want: %v, got %v", want, got)
+		}
+		if g.MustPass() || g.EndGame() {
+			t.Errorf("RandomBalancedLayout() = %v, want the first player to move", g)
+		}
+		score := NewSearcher(WithDepth(4)).Search(g.Position(1)).Score
+		if score < -balancedMargin || score > balancedMargin {
+			t.Errorf("RandomBalancedLayout() = %v, score %v, want within %v", g, score, balancedMargin)
+		}
+	}
+}
+
+func TestRandomObstacles(t *testing.T) {
+	for _, size := range []int{6, 8, 10} {
+		blocked := RandomObstacles(rand.New(rand.NewSource(3)), size, 5)
+		if len(blocked) != 4 {
+			t.Fatalf("len(RandomObstacles(%v, 5)), want: 4, got %v", size, len(blocked))
+		}
+		seen := make(map[Point]bool)
+		for i := 0; i < len(blocked); i += 2 {
+			p, q := blocked[i], blocked[i+1]
+			if q != (Point{size - 1 - p.X, size - 1 - p.Y}) {
+				t.Errorf("RandomObstacles(%v): %v and %v are not mirrored", size, p, q)
+			}
+			seen[p], seen[q] = true, true
+		}
+		g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithSize(size), WithBlocked(blocked...))
+		if len(seen) != 4 || g.P1().Score() != 2 || g.P2().Score() != 2 {
+			t.Errorf("RandomObstacles(%v) = %v, want 4 distinct empty cells", size, blocked)
+		}
+	}
+}
+
+func TestWithHandicap(t *testing.T) {
+	corners := []Point{{0, 0}, {7, 7}, {7, 0}, {0, 7}}
+	for n := 1; n <= MaxHandicap; n++ {
+		g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithHandicap(2, n))
+		for i, p := range corners {
+			want := 0
+			if i < n {
+				want = 2
+			}
+			if got := g.Cell(p); got != want {
+				t.Errorf("WithHandicap(2, %v): Cell(%v), want: %v, got %v", n, p, want, got)
+			}
+		}
+		if got, want := g.P2().Score(), 2+n; got != want {
+			t.Errorf("WithHandicap(2, %v): P2().Score(), want: %v, got %v", n, want, got)
+		}
+		if got, want := g.Handicap(), (Handicap{Token: 2, Corners: n}); got != want {
+			t.Errorf("WithHandicap(2, %v): Handicap(), want: %v, got %v", n, want, got)
+		}
+		if got, want := g.Hash(), mustParsePosition(t, g.String()).Hash(); got != want {
+			t.Errorf("WithHandicap(2, %v): Hash(), want: %v, got %v", n, want, got)
+		}
+	}
+
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithSize(6), WithBlocked(Point{0, 0}), WithHandicap(1, 2))
+	if g.Cell(Point{0, 0}) != Blocked || g.Cell(Point{5, 5}) != 1 || g.P1().Score() != 3 {
+		t.Errorf("WithHandicap(1, 2) with a1 blocked, got %v", g)
+	}
+
+	for _, h := range []Handicap{{1, 0}, {1, 5}, {3, 1}} {
+		g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithHandicap(h.Token, h.Corners))
+		if g.Handicap() != (Handicap{}) || g.P1().Score()+g.P2().Score() != 4 {
+			t.Errorf("WithHandicap(%v, %v), want no handicap, got %v", h.Token, h.Corners, g)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package reversi
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"math/rand"
+	"time"
+)
+
+// passMove marks the edge of a pass in the search tree.
+const passMove = -1
+
+type MCTSCfg struct {
+	exploration float64
+	playouts    int
+	timeLimit   time.Duration
+	seed        int64
+	reuseTree   bool
+	objective   Objective
+}
+
+type MCTSCfgFunc func(*MCTSCfg)
+
+func defaultMCTSConfig() MCTSCfg {
+	return MCTSCfg{
+		exploration: math.Sqrt2,
+		playouts:    2000,
+		seed:        time.Now().UnixNano(),
+	}
+}
+
+// WithExploration sets the UCT exploration constant. Higher values try less
+// visited moves more often.
+func WithExploration(c float64) MCTSCfgFunc {
+	return func(cfg *MCTSCfg) {
+		cfg.exploration = c
+	}
+}
+
+// WithPlayouts sets how many playouts are run per move.
+func WithPlayouts(n int) MCTSCfgFunc {
+	return func(cfg *MCTSCfg) {
+		cfg.playouts = n
+	}
+}
+
+// WithThinkingTime runs playouts until the time is up instead of a fixed
+// number of playouts.
+func WithThinkingTime(d time.Duration) MCTSCfgFunc {
+	return func(cfg *MCTSCfg) {
+		cfg.timeLimit = d
+	}
+}
+
+// WithSeed makes the playouts deterministic.
+func WithSeed(seed int64) MCTSCfgFunc {
+	return func(cfg *MCTSCfg) {
+		cfg.seed = seed
+	}
+}
+
+// WithTreeReuse keeps the subtree of the position reached after the previous
+// move and its replies, so earlier playouts are not thrown away.
+func WithTreeReuse(reuse bool) MCTSCfgFunc {
+	return func(cfg *MCTSCfg) {
+		cfg.reuseTree = reuse
+	}
+}
+
+// WithMCTSObjective makes the playouts count wins under objective.
+func WithMCTSObjective(objective Objective) MCTSCfgFunc {
+	return func(cfg *MCTSCfg) {
+		cfg.objective = objective
+	}
+}
+
+// MCTS picks moves with Monte Carlo Tree Search using the UCT formula and
+// random playouts. It needs no evaluation function.
+type MCTS struct {
+	cfg  MCTSCfg
+	rng  *rand.Rand
+	root *mctsNode
+}
+
+func NewMCTS(cfgFuncs ...MCTSCfgFunc) *MCTS {
+	cfg := defaultMCTSConfig()
+	for _, cfgFunc := range cfgFuncs {
+		cfgFunc(&cfg)
+	}
+	if cfg.playouts < 1 {
+		cfg.playouts = 1
+	}
+	return &MCTS{
+		cfg: cfg,
+		rng: rand.New(rand.NewSource(cfg.seed)),
+	}
+}
+
+type mctsNode struct {
+	pos      Position
+	move     int
+	parent   *mctsNode
+	children []*mctsNode
+	untried  uint64
+	// mustPass is set while the pass child of a stuck side is not expanded.
+	mustPass bool
+	visits   int
+	// wins is counted for the player who made move, a draw counting half.
+	wins float64
+}
+
+func newMCTSNode(pos Position, move int, parent *mctsNode) *mctsNode {
+	n := &mctsNode{pos: pos, move: move, parent: parent, untried: pos.Moves()}
+	n.mustPass = n.untried == 0 && pos.Pass().Moves() != 0
+	return n
+}
+
+func (n *mctsNode) expanded() bool {
+	return n.untried == 0 && !n.mustPass
+}
+
+// BestMove runs the playouts from p and returns the most visited move.
+func (m *MCTS) BestMove(p Position) (Point, error) {
+	if p.Moves() == 0 {
+		return Point{}, errors.New("no possible moves")
+	}
+
+	root := m.reusableRoot(p)
+	if root == nil {
+		root = newMCTSNode(p, passMove, nil)
+	}
+	root.parent = nil
+
+	deadline := time.Time{}
+	if m.cfg.timeLimit > 0 {
+		deadline = time.Now().Add(m.cfg.timeLimit)
+	}
+	for i := 0; ; i++ {
+		if deadline.IsZero() && i >= m.cfg.playouts {
+			break
+		}
+		if !deadline.IsZero() && i > 0 && time.Now().After(deadline) {
+			break
+		}
+		m.iterate(root)
+	}
+
+	best := root.children[0]
+	for _, c := range root.children[1:] {
+		if c.visits > best.visits {
+			best = c
+		}
+	}
+	if m.cfg.reuseTree {
+		m.root = best
+	}
+	return squarePoint(best.move), nil
+}
+
+// reusableRoot looks for p among the nodes reached by the last chosen move
+// and the opponent's reply.
+func (m *MCTS) reusableRoot(p Position) *mctsNode {
+	if !m.cfg.reuseTree || m.root == nil {
+		return nil
+	}
+	prev := m.root
+	m.root = nil
+	for _, c := range prev.children {
+		if c.pos == p {
+			return c
+		}
+	}
+	return nil
+}
+
+// iterate runs one selection, expansion, playout and backpropagation.
+func (m *MCTS) iterate(root *mctsNode) {
+	n := root
+	for n.expanded() && len(n.children) > 0 {
+		n = m.selectChild(n)
+	}
+
+	switch {
+	case n.mustPass:
+		n.mustPass = false
+		c := newMCTSNode(n.pos.Pass(), passMove, n)
+		n.children = append(n.children, c)
+		n = c
+	case n.untried != 0:
+		sq := nthBit(n.untried, m.rng.Intn(bits.OnesCount64(n.untried)))
+		n.untried &^= 1 << sq
+		c := newMCTSNode(n.pos.play(sq), sq, n)
+		n.children = append(n.children, c)
+		n = c
+	}
+
+	// The playout result is for the side to move at n, so the player who
+	// moved into n scores the opposite.
+	reward := 1 - m.playout(n.pos)
+	for ; n != nil; n = n.parent {
+		n.visits++
+		n.wins += reward
+		reward = 1 - reward
+	}
+}
+
+func (m *MCTS) selectChild(n *mctsNode) *mctsNode {
+	logVisits := math.Log(float64(n.visits))
+	var best *mctsNode
+	bestScore := math.Inf(-1)
+	for _, c := range n.children {
+		score := c.wins/float64(c.visits) + m.cfg.exploration*math.Sqrt(logVisits/float64(c.visits))
+		if score > bestScore {
+			best, bestScore = c, score
+		}
+	}
+	return best
+}
+
+// playout plays random moves to the end of the game and returns 1, 0.5 or 0
+// for a win, draw or loss of the side to move at p.
+func (m *MCTS) playout(p Position) float64 {
+	stm := true
+	for {
+		moves := p.Moves()
+		if moves == 0 {
+			if p.Pass().Moves() == 0 {
+				break
+			}
+			p, stm = p.Pass(), !stm
+			continue
+		}
+		p, stm = p.play(nthBit(moves, m.rng.Intn(bits.OnesCount64(moves)))), !stm
+	}
+
+	diff := m.cfg.objective.sign() * p.DiscDiff()
+	if !stm {
+		diff = -diff
+	}
+	switch {
+	case diff > 0:
+		return 1
+	case diff < 0:
+		return 0
+	}
+	return 0.5
+}
+
+// nthBit returns the index of the n-th set bit of mask, counted from 0.
+func nthBit(mask uint64, n int) int {
+	for ; n > 0; n-- {
+		mask &= mask - 1
+	}
+	return bits.TrailingZeros64(mask)
+}
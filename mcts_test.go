@@ -0,0 +1,88 @@
+package reversi
+
+import "testing"
+
+func TestMCTSDeterministic(t *testing.T) {
+	p := randomPosition(7, 40)
+	var first []Point
+	for run := 0; run < 2; run++ {
+		m := NewMCTS(WithSeed(42), WithPlayouts(300))
+		var moves []Point
+		q := p
+		for i := 0; i < 4 && q.Moves() != 0; i++ {
+			move, err := m.BestMove(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			moves = append(moves, move)
+			q = q.Play(move).Pass()
+		}
+		if run == 0 {
+			first = moves
+			continue
+		}
+		for i := range moves {
+			if moves[i] != first[i] {
+				t.Fatalf("BestMove() with the same seed, want: %v, got %v", first, moves)
+			}
+		}
+	}
+}
+
+func TestMCTSFindsWipeout(t *testing.T) {
+	p := positionFromGrid(t, [][]int{
+		{0, 2, 1, 0, 0, 0, 0, 0},
+		{2, 2, 0, 0, 0, 0, 0, 0},
+		{1, 0, 1, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+	}, 1)
+
+	move, err := NewMCTS(WithSeed(1), WithPlayouts(500)).BestMove(p)
+	if got, want := move, (Point{0, 0}); err != nil || got != want {
+		t.Errorf("BestMove(), want: %v, got (%v, %v)", want, got, err)
+	}
+}
+
+func TestMCTSTreeReuse(t *testing.T) {
+	m := NewMCTS(WithSeed(3), WithPlayouts(500), WithTreeReuse(true))
+	p := randomPosition(5, 30)
+	move, err := m.BestMove(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Answer with the reply the tree explored the most.
+	chosen := m.root
+	reply := chosen.children[0]
+	for _, c := range chosen.children {
+		if c.visits > reply.visits {
+			reply = c
+		}
+	}
+	if got, want := reply.pos, p.Play(move).play(reply.move); reply.move != passMove && got != want {
+		t.Fatalf("reply position, want: %v, got %v", want, got)
+	}
+
+	visits := reply.visits
+	if _, err := m.BestMove(reply.pos); err != nil {
+		t.Fatal(err)
+	}
+	if got := reply.visits; got != visits+500 {
+		t.Errorf("visits of reused root, want: %v, got %v", visits+500, got)
+	}
+}
+
+func TestMCTSPlayer(t *testing.T) {
+	p := NewPlayer(2, WithPlayerType(Computer), WithEngine(NewMCTS(WithSeed(1), WithPlayouts(100))))
+	g := NewGameBoard(*NewPlayer(1), *p)
+	g.Mark(Point{4, 2}, *g.P1())
+
+	move, err := g.P2().ChooseMove(g)
+	if err != nil || !g.P2().CanMove(move) {
+		t.Errorf("ChooseMove(), want a legal move, got (%v, %v)", move, err)
+	}
+}
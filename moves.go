@@ -0,0 +1,87 @@
+package reversi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseMoves splits a transcript of the standard board such as "f5d6c3" into
+// points. Spaces and commas between the moves are allowed and the letters may
+// be upper case.
+func ParseMoves(s string) ([]Point, error) {
+	return parseMoves(s, Width)
+}
+
+// parseMoves is ParseMoves on a board of the given size, where rows may take
+// two digits, e.g. "f5j10".
+func parseMoves(s string, size int) ([]Point, error) {
+	s = strings.ToLower(strings.NewReplacer(" ", "", ",", "", "\t", "").Replace(s))
+	var res []Point
+	for i := 0; i < len(s); {
+		j := i + 1
+		for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			j++
+		}
+		p, err := Notation(s[i:j]).ToPointOn(size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid move %q in transcript", s[i:j])
+		}
+		res = append(res, p)
+		i = j
+	}
+	return res, nil
+}
+
+// startPosition returns the starting position of NewGameBoard from the first
+// player.
+func startPosition() Position {
+	return Position{Own: squareBit(Point{3, 3}) | squareBit(Point{4, 4}), Opp: squareBit(Point{4, 3}) | squareBit(Point{3, 4})}
+}
+
+// orientMoves maps moves written for the standard Othello board onto this
+// one. The standard board has the discs of the first player on e4 and d5,
+// the mirror image of NewGameBoard, so standard games open with f5, d3, c4 or
+// e6 where games here open with c5, e3, f4 or d6. Moves that already fit this
+// board are returned as they are.
+func orientMoves(moves []Point) []Point {
+	if len(moves) == 0 || startPosition().Moves()&squareBit(moves[0]) != 0 {
+		return moves
+	}
+	res := make([]Point, len(moves))
+	for i, m := range moves {
+		res[i] = mirror(m)
+	}
+	return res
+}
+
+// mirror reflects p between the columns a and h.
+func mirror(p Point) Point {
+	return mirrorOn(p, Width)
+}
+
+// mirrorOn reflects p between the first and the last column of a board of the
+// given size.
+func mirrorOn(p Point, size int) Point {
+	return Point{size - 1 - p.X, p.Y}
+}
+
+// replayMoves plays moves from the starting position, passing when the side
+// to move cannot play. It returns the positions after each move, seen from
+// the side to move.
+func replayMoves(moves []Point) ([]Position, error) {
+	moves = orientMoves(moves)
+	res := make([]Position, 0, len(moves))
+	p := startPosition()
+	for i, m := range moves {
+		if p.Moves() == 0 {
+			p = p.Pass()
+		}
+		if p.Moves()&squareBit(m) == 0 {
+			n, _ := m.ToNotation()
+			return nil, fmt.Errorf("illegal move %v at move %d", n, i+1)
+		}
+		p = p.Play(m)
+		res = append(res, p)
+	}
+	return res, nil
+}
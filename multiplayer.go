@@ -0,0 +1,47 @@
+package reversi
+
+// MaxPlayers is the largest number of players in a game.
+const MaxPlayers = 4
+
+// WithMorePlayers seats players after p1 and p2, holding tokens 3 and 4, for
+// a game of up to MaxPlayers. Players take turns in the order of their
+// tokens and a move flips the discs of any opponent bracketed by the discs of
+// the player. Games of more than two players are played without the engines;
+// a larger board such as 10x10 gives every player room. Players beyond
+// MaxPlayers are ignored.
+func WithMorePlayers(players ...Player) GameCfgFunc {
+	return func(cfg *GameCfg) {
+		cfg.more = append(cfg.more, players...)
+	}
+}
+
+// multiStart puts one disc of each of n players on the four centre cells,
+// clockwise from the top left, for games of more than two players. The
+// bottom left cell stays empty with three players.
+func multiStart(board [][]int, n int) {
+	c := len(board) / 2
+	cells := []Point{{c - 1, c - 1}, {c, c - 1}, {c, c}, {c - 1, c}}
+	for i, p := range cells[:n] {
+		board[p.Y][p.X] = i + 1
+	}
+}
+
+// owners returns the tokens of the discs on points.
+func (g GameBoard) owners(points []Point) []int {
+	res := make([]int, len(points))
+	for i, p := range points {
+		res[i] = g.board[p.Y][p.X]
+	}
+	return res
+}
+
+// next returns the token moving after token.
+func (g GameBoard) next(token int) int {
+	return token%len(g.players) + 1
+}
+
+// setToMove hands the turn to the player holding token.
+func (g *GameBoard) setToMove(token int) {
+	g.hash ^= zobristTurn[g.toMove-1] ^ zobristTurn[token-1]
+	g.toMove = token
+}
@@ -0,0 +1,129 @@
+package reversi
+
+import "testing"
+
+// morePlayers returns the players holding tokens 3 up to n.
+func morePlayers(n int) GameCfgFunc {
+	var players []Player
+	for token := 3; token <= n; token++ {
+		players = append(players, *NewPlayer(token))
+	}
+	return WithMorePlayers(players...)
+}
+
+func TestMultiplayerStart(t *testing.T) {
+	for n := 3; n <= MaxPlayers; n++ {
+		g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithSize(10), morePlayers(n))
+		if got := len(g.Players()); got != n {
+			t.Fatalf("%v players: len(Players()), want: %v, got %v", n, n, got)
+		}
+		for _, p := range g.Players() {
+			if p.Score() != 1 || len(p.PossibleMoves()) == 0 {
+				t.Errorf("%v players: player %v starts with %v discs and %v moves, want 1 disc and some moves", n, p.Token(), p.Score(), len(p.PossibleMoves()))
+			}
+		}
+	}
+
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), morePlayers(MaxPlayers+1))
+	if got, want := len(g.Players()), MaxPlayers; got != want {
+		t.Errorf("len(Players()) with too many players, want: %v, got %v", want, got)
+	}
+}
+
+func TestMultiplayerFlipsEveryOpponent(t *testing.T) {
+	g, err := ParsePosition(`
+		-OYX--
+		------
+		--XZ--
+		--ZO--
+		------
+		------
+		X`, *NewPlayer(1), *NewPlayer(2), morePlayers(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.PossibleMoves(1)[Point{0, 0}], []Point{{1, 0}, {2, 0}}; !equalMapUnorderedSlice(map[Point][]Point{{}: got}, map[Point][]Point{{}: want}) {
+		t.Errorf("PossibleMoves(1)[a1], want: %v, got %v", want, got)
+	}
+
+	start, hash := g.String(), g.Hash()
+	if _, err := g.Mark(Point{0, 0}, *g.P1()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.CurrentPlayer().Token(), 2; got != want {
+		t.Errorf("CurrentPlayer() after the move, want: %v, got %v", want, got)
+	}
+	if g.P1().Score() != 5 || g.P2().Score() != 1 || g.Players()[2].Score() != 0 {
+		t.Errorf("scores after the move, got %v", g)
+	}
+	if err := g.Undo(); err != nil || g.String() != start || g.Hash() != hash {
+		t.Errorf("Undo(), want: %v, got %v", start, g)
+	}
+
+	if _, err := ParsePosition(start, *NewPlayer(1), *NewPlayer(2), morePlayers(3)); err == nil {
+		t.Errorf("ParsePosition() with a disc of a missing player, want error, got nil")
+	}
+}
+
+func TestMultiplayerGames(t *testing.T) {
+	for n := 3; n <= MaxPlayers; n++ {
+		for seed := int64(0); seed < 5; seed++ {
+			var last *GameBoard
+			playRandomGameWith(seed, func(g *GameBoard) {
+				discs := 0
+				for _, p := range g.Players() {
+					discs += p.Score()
+				}
+				if discs != n+len(g.History())-passes(g) {
+					t.Fatalf("%v players seed %v: scores out of sync", n, seed)
+				}
+				h, err := ParsePosition(g.String(), *NewPlayer(1), *NewPlayer(2), morePlayers(n))
+				if err != nil || h.Hash() != g.Hash() {
+					t.Fatalf("%v players seed %v: ParsePosition(%v) = %v, %v", n, seed, g, h, err)
+				}
+				last = g
+			}, WithSize(10), morePlayers(n))
+
+			// The players take turns in the order of their tokens, skipping
+			// those who pass.
+			for i, m := range last.History() {
+				if got, want := m.Token, i%n+1; got != want {
+					t.Fatalf("%v players seed %v: move %v played by %v, want %v", n, seed, i+1, got, want)
+				}
+			}
+			for last.CanUndo() {
+				last.Undo()
+			}
+			if got, want := last.String(), NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithSize(10), morePlayers(n)).String(); got != want {
+				t.Errorf("%v players seed %v: Undo() to the start, want: %v, got %v", n, seed, want, got)
+			}
+		}
+	}
+}
+
+func TestMultiplayerResult(t *testing.T) {
+	g, err := ParsePosition(`
+		XXYY
+		X---
+		OOO-
+		----
+		X`, *NewPlayer(1), *NewPlayer(2), morePlayers(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Result(); got != nil {
+		t.Errorf("Result() with a tie for the most discs, want: nil, got %v", got.Name())
+	}
+	g.Surrender(g.P1())
+	if got, want := g.Result(), g.P2(); got != want {
+		t.Errorf("Result() after P1 surrendered, want: %v, got %v", want.Name(), got)
+	}
+
+	g, err = ParsePosition(g.String(), *NewPlayer(1), *NewPlayer(2), morePlayers(3), WithObjective(FewestDiscs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.Result(), g.Players()[2]; got != want {
+		t.Errorf("Result() under FewestDiscs, want: %v, got %v", want.Name(), got)
+	}
+}
@@ -0,0 +1,301 @@
+package reversi
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultNBoardDepth is the search depth asked of NBoard engines unless
+	// WithNBoardDepth says otherwise.
+	DefaultNBoardDepth = 12
+	// DefaultNBoardStartTimeout and DefaultNBoardMoveTimeout bound the time an
+	// NBoard engine may take to start and to choose a move.
+	DefaultNBoardStartTimeout = 5 * time.Second
+	DefaultNBoardMoveTimeout  = 30 * time.Second
+	// nboardCloseWait is how long Close waits for the engine to exit once its
+	// input is closed before killing it.
+	nboardCloseWait = time.Second
+)
+
+// NBoardEngine is an Engine backed by an external program speaking the NBoard
+// protocol on its standard input and output. The program is started by
+// StartNBoardEngine and restarted by BestMove after it crashes or times out,
+// until Close. An NBoardEngine is safe for concurrent use, one move at a time.
+type NBoardEngine struct {
+	path string
+	args []string
+	cfg  NBoardCfg
+
+	mu    sync.Mutex
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	// lines carries the lines written by the engine. It is closed when the
+	// engine exits.
+	lines chan string
+	name  string
+	ping  int
+	// closed is set by Close, after which the engine is never started again.
+	closed bool
+}
+
+type NBoardCfg struct {
+	depth        int
+	startTimeout time.Duration
+	moveTimeout  time.Duration
+	stderr       io.Writer
+}
+
+type NBoardCfgFunc func(cfg *NBoardCfg)
+
+// WithNBoardDepth sets the search depth the engine is asked to play at.
+func WithNBoardDepth(depth int) NBoardCfgFunc {
+	return func(cfg *NBoardCfg) {
+		cfg.depth = depth
+	}
+}
+
+// WithNBoardStartTimeout bounds the time the engine may take to start and
+// answer the first ping.
+func WithNBoardStartTimeout(timeout time.Duration) NBoardCfgFunc {
+	return func(cfg *NBoardCfg) {
+		cfg.startTimeout = timeout
+	}
+}
+
+// WithNBoardMoveTimeout bounds the time the engine may take per move. An
+// engine out of time is killed and started again on the next move.
+func WithNBoardMoveTimeout(timeout time.Duration) NBoardCfgFunc {
+	return func(cfg *NBoardCfg) {
+		cfg.moveTimeout = timeout
+	}
+}
+
+// WithNBoardStderr sends what the engine writes on its standard error to w.
+// It is discarded by default.
+func WithNBoardStderr(w io.Writer) NBoardCfgFunc {
+	return func(cfg *NBoardCfg) {
+		cfg.stderr = w
+	}
+}
+
+// StartNBoardEngine starts the program at path with args and waits until it
+// speaks the NBoard protocol.
+func StartNBoardEngine(path string, args []string, cfgFuncs ...NBoardCfgFunc) (*NBoardEngine, error) {
+	cfg := NBoardCfg{
+		depth:        DefaultNBoardDepth,
+		startTimeout: DefaultNBoardStartTimeout,
+		moveTimeout:  DefaultNBoardMoveTimeout,
+	}
+	for _, cfgFunc := range cfgFuncs {
+		cfgFunc(&cfg)
+	}
+	e := &NBoardEngine{path: path, args: args, cfg: cfg}
+	if err := e.start(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Name returns the name the engine gave with "set myname", or its path if
+// none.
+func (e *NBoardEngine) Name() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.name == "" {
+		return e.path
+	}
+	return e.name
+}
+
+// BestMove asks the engine for its move in p. The engine is started again
+// first if it is no longer running. It fails once the engine is closed.
+func (e *NBoardEngine) BestMove(p Position) (Point, error) {
+	if p.Moves() == 0 {
+		return Point{}, errors.New("no possible moves")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return Point{}, fmt.Errorf("engine %v is closed", e.path)
+	}
+	if e.cmd == nil {
+		if err := e.start(); err != nil {
+			return Point{}, err
+		}
+	}
+
+	deadline := time.After(e.cfg.moveTimeout)
+	if err := e.send("set game " + nboardGame(p)); err != nil {
+		return Point{}, err
+	}
+	if err := e.sync(deadline); err != nil {
+		return Point{}, err
+	}
+	if err := e.send("go"); err != nil {
+		return Point{}, err
+	}
+	line, err := e.await("===", deadline)
+	if err != nil {
+		return Point{}, err
+	}
+	// The reply is "=== F5", optionally followed by "/eval/time".
+	move, _, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(line, "===")), "/")
+	point, err := Notation(strings.ToLower(move)).ToPoint()
+	if err != nil || p.Moves()&squareBit(point) == 0 {
+		return Point{}, fmt.Errorf("engine %v played illegal move %q", e.path, move)
+	}
+	return point, nil
+}
+
+// Close stops the engine, killing it if it does not exit once its input is
+// closed. A move being searched is finished first.
+func (e *NBoardEngine) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	if e.cmd == nil {
+		return nil
+	}
+	e.stdin.Close()
+	select {
+	case <-drain(e.lines):
+	case <-time.After(nboardCloseWait):
+	}
+	e.kill()
+	return nil
+}
+
+// start runs the program and waits for it to answer a ping.
+func (e *NBoardEngine) start() error {
+	cmd := exec.Command(e.path, e.args...)
+	cmd.Stderr = e.cfg.stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("cannot start engine %v: %w", e.path, err)
+	}
+	lines := make(chan string, 64)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		cmd.Wait()
+		close(lines)
+	}()
+	e.cmd, e.stdin, e.lines = cmd, stdin, lines
+
+	deadline := time.After(e.cfg.startTimeout)
+	if err := e.send("nboard 2"); err != nil {
+		return err
+	}
+	if err := e.send(fmt.Sprintf("set depth %d", e.cfg.depth)); err != nil {
+		return err
+	}
+	return e.sync(deadline)
+}
+
+// send writes a command to the engine.
+func (e *NBoardEngine) send(command string) error {
+	if _, err := io.WriteString(e.stdin, command+"\n"); err != nil {
+		e.kill()
+		return fmt.Errorf("engine %v stopped: %w", e.path, err)
+	}
+	return nil
+}
+
+// sync pings the engine and waits for the matching pong, so every earlier
+// command has been handled.
+func (e *NBoardEngine) sync(deadline <-chan time.Time) error {
+	e.ping++
+	if err := e.send(fmt.Sprintf("ping %d", e.ping)); err != nil {
+		return err
+	}
+	pong := fmt.Sprintf("pong %d", e.ping)
+	for {
+		line, err := e.await("pong", deadline)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(line) == pong {
+			return nil
+		}
+	}
+}
+
+// await returns the next line of the engine starting with prefix, noting
+// the name of the engine on the way. The engine is killed if it exits or
+// deadline passes first.
+func (e *NBoardEngine) await(prefix string, deadline <-chan time.Time) (string, error) {
+	for {
+		select {
+		case line, ok := <-e.lines:
+			if !ok {
+				e.kill()
+				return "", fmt.Errorf("engine %v exited", e.path)
+			}
+			if name, ok := strings.CutPrefix(line, "set myname "); ok {
+				e.name = strings.TrimSpace(name)
+			}
+			if strings.HasPrefix(line, prefix) {
+				return line, nil
+			}
+		case <-deadline:
+			e.kill()
+			return "", fmt.Errorf("engine %v timed out", e.path)
+		}
+	}
+}
+
+// kill stops the engine at once. The next move starts it again.
+func (e *NBoardEngine) kill() {
+	if e.cmd == nil {
+		return
+	}
+	e.stdin.Close()
+	e.cmd.Process.Kill()
+	// Wait for the reader to reap the process.
+	<-drain(e.lines)
+	e.cmd, e.stdin, e.lines = nil, nil, nil
+}
+
+// drain discards the lines left on lines and closes the returned channel
+// once lines is closed.
+func drain(lines <-chan string) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		for range lines {
+		}
+		close(done)
+	}()
+	return done
+}
+
+// nboardGame writes p as a GGF game with no moves, the side to move as black.
+func nboardGame(p Position) string {
+	var sb strings.Builder
+	for sq := 0; sq < Width*Height; sq++ {
+		switch {
+		case p.Own&(1<<sq) != 0:
+			sb.WriteByte('*')
+		case p.Opp&(1<<sq) != 0:
+			sb.WriteByte('O')
+		default:
+			sb.WriteByte('-')
+		}
+	}
+	return fmt.Sprintf("(;GM[Othello]PC[reversi]TY[8]BO[8 %s *];)", sb.String())
+}
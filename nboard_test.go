@@ -0,0 +1,224 @@
+package reversi
+
+import (
+	"bufio"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeNBoardEnv makes the test binary act as the fake engine of the mode it
+// holds, see TestFakeNBoardEngine.
+const (
+	fakeNBoardEnv    = "REVERSI_FAKE_NBOARD"
+	fakeNBoardMarker = "REVERSI_FAKE_NBOARD_MARKER"
+)
+
+// TestFakeNBoardEngine is not a test but the fake engine run by the other
+// tests. It plays the first legal move, except in these modes:
+//   - "silent" never answers.
+//   - "hang" never answers go.
+//   - "crash" exits on go.
+//   - "crash-once" exits on go unless the file named by fakeNBoardMarker
+//     exists, and creates it.
+//   - "illegal" always plays a1.
+func TestFakeNBoardEngine(t *testing.T) {
+	mode := os.Getenv(fakeNBoardEnv)
+	if mode == "" {
+		t.Skip("only run as a fake engine")
+	}
+	if mode == "silent" {
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+	var p Position
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		cmd, arg, _ := strings.Cut(scanner.Text(), " ")
+		switch cmd {
+		case "nboard":
+			fmt.Println("set myname Fake")
+		case "ping":
+			fmt.Println("pong", arg)
+		case "set":
+			if game, ok := strings.CutPrefix(arg, "game "); ok {
+				p = fakeNBoardPosition(game)
+			}
+		case "go":
+			switch mode {
+			case "hang":
+				continue
+			case "crash":
+				os.Exit(1)
+			case "crash-once":
+				marker := os.Getenv(fakeNBoardMarker)
+				if _, err := os.Stat(marker); err != nil {
+					os.WriteFile(marker, nil, 0o644)
+					os.Exit(1)
+				}
+			case "illegal":
+				fmt.Println("=== A1")
+				continue
+			}
+			fmt.Printf("status thinking\n=== %s/0.00/0.1\n", strings.ToUpper(string(mustNotation(squarePoint(bits.TrailingZeros64(p.Moves()))))))
+		}
+	}
+	os.Exit(0)
+}
+
+// fakeNBoardPosition reads the BO property of a game written by nboardGame.
+func fakeNBoardPosition(game string) Position {
+	_, bo, _ := strings.Cut(game, "BO[8 ")
+	var p Position
+	for sq := 0; sq < Width*Height; sq++ {
+		switch bo[sq] {
+		case '*':
+			p.Own |= 1 << sq
+		case 'O':
+			p.Opp |= 1 << sq
+		}
+	}
+	return p
+}
+
+func mustNotation(p Point) Notation {
+	n, _ := p.ToNotation()
+	return n
+}
+
+// startFakeNBoardEngine starts the test binary as a fake engine in mode.
+func startFakeNBoardEngine(t *testing.T, mode string, cfgFuncs ...NBoardCfgFunc) (*NBoardEngine, error) {
+	t.Helper()
+	t.Setenv(fakeNBoardEnv, mode)
+	t.Setenv(fakeNBoardMarker, filepath.Join(t.TempDir(), "crashed"))
+	e, err := StartNBoardEngine(os.Args[0], []string{"-test.run=^TestFakeNBoardEngine$"}, cfgFuncs...)
+	if e != nil {
+		t.Cleanup(func() { e.Close() })
+	}
+	return e, err
+}
+
+func TestNBoardEngine(t *testing.T) {
+	e, err := startFakeNBoardEngine(t, "ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.Name(), "Fake"; got != want {
+		t.Errorf("Name(), want: %v, got %v", want, got)
+	}
+	p := startPosition()
+	for p.Moves() != 0 {
+		move, err := e.BestMove(p)
+		if err != nil {
+			t.Fatalf("BestMove(%v): %v", p, err)
+		}
+		if want := squarePoint(bits.TrailingZeros64(p.Moves())); move != want {
+			t.Fatalf("BestMove(%v), want: %v, got %v", p, want, move)
+		}
+		p = p.Play(move)
+		if p.Moves() == 0 {
+			p = p.Pass()
+		}
+	}
+	if _, err := e.BestMove(p); err == nil {
+		t.Errorf("BestMove of a finished game, want error, got nil")
+	}
+}
+
+func TestNBoardEngineClosed(t *testing.T) {
+	e, err := startFakeNBoardEngine(t, "ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.BestMove(startPosition()); err == nil || !strings.Contains(err.Error(), "closed") {
+		t.Errorf("BestMove() after Close(), want a closed error, got %v", err)
+	}
+	if e.cmd != nil {
+		t.Error("BestMove() after Close() started the engine again")
+	}
+	if err := e.Close(); err != nil {
+		t.Errorf("second Close(), want nil, got %v", err)
+	}
+}
+
+func TestNBoardEngineFailures(t *testing.T) {
+	tests := map[string]struct {
+		mode    string
+		wantErr string
+	}{
+		"timeout": {"hang", "timed out"},
+		"crash":   {"crash", "exited"},
+		"illegal": {"illegal", "illegal move"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e, err := startFakeNBoardEngine(t, tt.mode, WithNBoardMoveTimeout(200*time.Millisecond))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = e.BestMove(startPosition())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("BestMove, want error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNBoardEngineRestarts(t *testing.T) {
+	e, err := startFakeNBoardEngine(t, "crash-once")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.BestMove(startPosition()); err == nil {
+		t.Fatalf("first BestMove, want error, got nil")
+	}
+	if _, err := e.BestMove(startPosition()); err != nil {
+		t.Errorf("BestMove after a crash, want nil, got %v", err)
+	}
+}
+
+func TestNBoardEngineStartTimeout(t *testing.T) {
+	start := time.Now()
+	_, err := startFakeNBoardEngine(t, "silent", WithNBoardStartTimeout(200*time.Millisecond))
+	if err == nil {
+		t.Fatalf("StartNBoardEngine, want error, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("StartNBoardEngine took %v", elapsed)
+	}
+}
+
+func TestNBoardEnginePlaysGames(t *testing.T) {
+	e, err := startFakeNBoardEngine(t, "ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGameBoard(*NewPlayer(1, WithPlayerType(Computer), WithEngine(e)), *NewPlayer(2))
+	for !g.EndGame() {
+		if g.MustPass() {
+			g.Pass()
+			continue
+		}
+		p := g.CurrentPlayer()
+		var move Point
+		var err error
+		if p.Type() == Computer {
+			move, err = p.ChooseMove(g)
+		} else {
+			move, err = p.randomChooseMove()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := g.Mark(move, *p); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package reversi
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed openings.txt
+var openingsData string
+
+// openingNames maps the canonical position at the end of each named line to
+// its name.
+var openingNames = parseOpeningNames(openingsData)
+
+func parseOpeningNames(data string) map[Position]string {
+	res := make(map[Position]string)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		moves, name, _ := strings.Cut(line, " ")
+		points, err := ParseMoves(moves)
+		if err != nil {
+			panic("openings.txt: " + err.Error())
+		}
+		positions, err := replayMoves(points)
+		if err != nil {
+			panic("openings.txt: " + err.Error())
+		}
+		res[positions[len(positions)-1].Canonical()] = strings.TrimSpace(name)
+	}
+	return res
+}
+
+// OpeningName returns the name of the opening line ending in p, seen from
+// the side to move. Only the exact final position of a line is named, so
+// callers wanting the name of a game keep the last one found.
+func OpeningName(p Position) (string, bool) {
+	name, ok := openingNames[p.Canonical()]
+	return name, ok
+}
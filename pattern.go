@@ -0,0 +1,306 @@
+package reversi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"os"
+)
+
+const (
+	// patternStages is the number of game stages with their own weights,
+	// from the opening to the last moves.
+	patternStages = 6
+	// patternScale is the number of evaluation points per disc.
+	patternScale = 100
+)
+
+// patternMagic starts the files written by PatternEval.WriteTo.
+var patternMagic = [4]byte{'R', 'V', 'P', 'E'}
+
+// patternShapes are the squares of each pattern near corner a1. A pattern
+// covers the copies of its shape under the 8 symmetries of the board, which
+// share the weights, so symmetric positions get the same evaluation.
+var patternShapes = [][]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 9, 14},   // edge a1-h1 with the X-squares b2, g2
+	{0, 1, 2, 8, 9, 10, 16, 17, 18},   // corner 3x3
+	{0, 1, 2, 3, 4, 8, 9, 10, 11, 12}, // corner 2x5
+	{0, 9, 18, 27, 36, 45, 54, 63},    // diagonal a1-h8
+	{1, 10, 19, 28, 37, 46, 55},       // diagonal b1-h7
+	{2, 11, 20, 29, 38, 47},           // diagonal c1-h6
+	{3, 12, 21, 30, 39},               // diagonal d1-h5
+	{4, 13, 22, 31},                   // diagonal e1-h4
+}
+
+// patternInstance is one copy of a shape on the board, with the offset of
+// the weights of the shape.
+type patternInstance struct {
+	squares []int
+	offset  int
+}
+
+var (
+	patternInstances []patternInstance
+	// patternWeights is the number of weights of a stage, the last one
+	// being the bias.
+	patternWeights int
+)
+
+func init() {
+	for _, shape := range patternShapes {
+		// Shapes such as the diagonal a1-h8 are their own copy under some
+		// symmetries.
+		seen := make(map[string]bool)
+		for i := 0; i < 8; i++ {
+			squares := make([]int, len(shape))
+			for k, sq := range shape {
+				squares[k] = bits.TrailingZeros64(symmetry(1<<sq, i))
+			}
+			key := fmt.Sprint(squares)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			patternInstances = append(patternInstances, patternInstance{squares: squares, offset: patternWeights})
+		}
+		patternWeights += pow3(len(shape))
+	}
+	patternWeights++
+}
+
+func pow3(n int) int {
+	res := 1
+	for ; n > 0; n-- {
+		res *= 3
+	}
+	return res
+}
+
+// patternStage returns the stage of a position with empties empty cells.
+func patternStage(empties int) int {
+	return (Width*Height - 4 - empties) * patternStages / (Width*Height - 3)
+}
+
+// patternFeatures appends to dst the indexes of the weights taking part in
+// the evaluation of p within its stage: one per pattern instance and the
+// bias.
+func patternFeatures(dst []int32, p Position) []int32 {
+	for _, inst := range patternInstances {
+		idx := 0
+		for k := len(inst.squares) - 1; k >= 0; k-- {
+			bit := uint64(1) << inst.squares[k]
+			idx *= 3
+			if p.Own&bit != 0 {
+				idx++
+			} else if p.Opp&bit != 0 {
+				idx += 2
+			}
+		}
+		dst = append(dst, int32(inst.offset+idx))
+	}
+	return append(dst, int32(patternWeights-1))
+}
+
+// PatternEval evaluates positions with weights for every configuration of
+// edge, corner and diagonal patterns, one set per stage of the game. The
+// weights predict the final disc difference and are fitted to self-play
+// games by TrainPatternEval.
+type PatternEval struct {
+	weights [patternStages][]float32
+}
+
+// NewPatternEval returns a PatternEval with every weight at zero.
+func NewPatternEval() *PatternEval {
+	e := &PatternEval{}
+	for i := range e.weights {
+		e.weights[i] = make([]float32, patternWeights)
+	}
+	return e
+}
+
+// Predict returns the expected final disc difference of the side to move in
+// p.
+func (e *PatternEval) Predict(p Position) float64 {
+	var buf [80]int32
+	w := e.weights[patternStage(p.Empties())]
+	sum := float32(0)
+	for _, f := range patternFeatures(buf[:0], p) {
+		sum += w[f]
+	}
+	return float64(sum)
+}
+
+// Evaluate scores p in hundredths of a disc, so a PatternEval can be given
+// to WithEvaluator as e.Evaluate.
+func (e *PatternEval) Evaluate(p Position) int {
+	return int(math.Round(e.Predict(p) * patternScale))
+}
+
+// WriteTo writes the weights in binary form, read back by ReadPatternEval.
+// It returns the number of bytes written to w.
+func (e *PatternEval) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	if _, err := bw.Write(patternMagic[:]); err != nil {
+		return cw.n, err
+	}
+	for _, stage := range e.weights {
+		if err := binary.Write(bw, binary.LittleEndian, stage); err != nil {
+			return cw.n, err
+		}
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(b []byte) (int, error) {
+	n, err := cw.w.Write(b)
+	cw.n += int64(n)
+	return n, err
+}
+
+// ReadPatternEval reads weights written by PatternEval.WriteTo.
+func ReadPatternEval(r io.Reader) (*PatternEval, error) {
+	br := bufio.NewReader(r)
+	var magic [4]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil || magic != patternMagic {
+		return nil, errors.New("not a pattern evaluation file")
+	}
+	e := NewPatternEval()
+	for i := range e.weights {
+		if err := binary.Read(br, binary.LittleEndian, e.weights[i]); err != nil {
+			return nil, fmt.Errorf("reading stage %d: %w", i+1, err)
+		}
+	}
+	return e, nil
+}
+
+// LoadPatternEvalFile reads the weights of the named file.
+func LoadPatternEvalFile(name string) (*PatternEval, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadPatternEval(f)
+}
+
+type TrainCfg struct {
+	epochs     int
+	rate       float64
+	ridge      float64
+	onProgress func(epoch int, rmse float64)
+}
+
+type TrainCfgFunc func(cfg *TrainCfg)
+
+// WithTrainEpochs sets the number of passes over the samples, 100 by default.
+func WithTrainEpochs(epochs int) TrainCfgFunc {
+	return func(cfg *TrainCfg) {
+		cfg.epochs = epochs
+	}
+}
+
+// WithTrainRate scales the steps of the weights, 1 by default. Lower it if
+// the error goes up between epochs.
+func WithTrainRate(rate float64) TrainCfgFunc {
+	return func(cfg *TrainCfg) {
+		cfg.rate = rate
+	}
+}
+
+// WithTrainRidge sets the penalty on the squared weights, 100 by default,
+// which keeps the rarely seen configurations near zero.
+func WithTrainRidge(ridge float64) TrainCfgFunc {
+	return func(cfg *TrainCfg) {
+		cfg.ridge = ridge
+	}
+}
+
+// WithTrainProgress calls onProgress after every epoch with the root mean
+// square error of the predictions of that epoch, in discs.
+func WithTrainProgress(onProgress func(epoch int, rmse float64)) TrainCfgFunc {
+	return func(cfg *TrainCfg) {
+		cfg.onProgress = onProgress
+	}
+}
+
+// TrainPatternEval fits the weights of a PatternEval to the final outcome of
+// the samples by least squares with a ridge penalty. Each epoch moves every
+// weight by the mean error of the samples it takes part in, so weights seen
+// in few samples stay stable. It returns the evaluation and the root mean
+// square error of its predictions over the samples.
+func TrainPatternEval(samples []Sample, cfgFuncs ...TrainCfgFunc) (*PatternEval, float64) {
+	cfg := TrainCfg{epochs: 100, rate: 1, ridge: 100}
+	for _, cfgFunc := range cfgFuncs {
+		cfgFunc(&cfg)
+	}
+
+	e := NewPatternEval()
+	features := len(patternInstances) + 1
+	stages := make([]int, len(samples))
+	index := make([]int32, 0, len(samples)*features)
+	var counts [patternStages][]float64
+	for i := range counts {
+		counts[i] = make([]float64, patternWeights)
+	}
+	for i, s := range samples {
+		stages[i] = patternStage(s.Position.Empties())
+		index = patternFeatures(index, s.Position)
+		for _, f := range index[i*features:] {
+			counts[stages[i]][f]++
+		}
+	}
+
+	var grads [patternStages][]float64
+	for i := range grads {
+		grads[i] = make([]float64, patternWeights)
+	}
+	step := cfg.rate / float64(features)
+	for epoch := 1; epoch <= cfg.epochs; epoch++ {
+		sq := 0.0
+		for i, s := range samples {
+			w, g := e.weights[stages[i]], grads[stages[i]]
+			fs := index[i*features : (i+1)*features]
+			pred := float32(0)
+			for _, f := range fs {
+				pred += w[f]
+			}
+			r := float64(s.Outcome) - float64(pred)
+			sq += r * r
+			for _, f := range fs {
+				g[f] += r
+			}
+		}
+		for st := range grads {
+			w, g, c := e.weights[st], grads[st], counts[st]
+			for f := range g {
+				if c[f] == 0 {
+					continue
+				}
+				w[f] += float32(step * (g[f] - cfg.ridge*float64(w[f])) / (c[f] + cfg.ridge))
+				g[f] = 0
+			}
+		}
+		if cfg.onProgress != nil {
+			cfg.onProgress(epoch, math.Sqrt(sq/float64(max(len(samples), 1))))
+		}
+	}
+
+	sq := 0.0
+	for _, s := range samples {
+		r := float64(s.Outcome) - e.Predict(s.Position)
+		sq += r * r
+	}
+	return e, math.Sqrt(sq / float64(max(len(samples), 1)))
+}
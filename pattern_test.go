@@ -0,0 +1,134 @@
+package reversi
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// selfPlaySamples returns the samples of quick self-play games.
+func selfPlaySamples(t *testing.T, games int) []Sample {
+	t.Helper()
+	var samples []Sample
+	err := SelfPlay(games, func(s []Sample) error {
+		samples = append(samples, s...)
+		return nil
+	}, WithSelfPlaySearch(WithDepth(1)), WithSelfPlaySeed(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return samples
+}
+
+func TestPatternInstances(t *testing.T) {
+	if got, want := len(patternInstances), 60; got != want {
+		t.Errorf("pattern instances, want: %v, got %v", want, got)
+	}
+	for _, inst := range patternInstances {
+		var mask uint64
+		for _, sq := range inst.squares {
+			mask |= 1 << sq
+		}
+		if len(inst.squares) != bits.OnesCount64(mask) {
+			t.Errorf("instance %v, want distinct squares", inst.squares)
+		}
+	}
+	if got, want := patternStage(60), 0; got != want {
+		t.Errorf("patternStage(60), want: %v, got %v", want, got)
+	}
+	if got, want := patternStage(0), patternStages-1; got != want {
+		t.Errorf("patternStage(0), want: %v, got %v", want, got)
+	}
+}
+
+func TestPatternEvalSymmetric(t *testing.T) {
+	e := NewPatternEval()
+	rng := rand.New(rand.NewSource(1))
+	for _, w := range e.weights {
+		for i := range w {
+			w[i] = rng.Float32()*2 - 1
+		}
+	}
+	for _, s := range selfPlaySamples(t, 2) {
+		want := e.Predict(s.Position)
+		for i := 1; i < 8; i++ {
+			q := Position{Own: symmetry(s.Position.Own, i), Opp: symmetry(s.Position.Opp, i)}
+			if got := e.Predict(q); math.Abs(got-want) > 1e-3 {
+				t.Fatalf("Predict() under symmetry %d, want: %v, got %v", i, want, got)
+			}
+		}
+	}
+}
+
+// failingWriter fails once limit bytes are written.
+type failingWriter struct {
+	limit int
+}
+
+func (w *failingWriter) Write(b []byte) (int, error) {
+	if len(b) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, errors.New("disk full")
+	}
+	w.limit -= len(b)
+	return len(b), nil
+}
+
+func TestTrainPatternEval(t *testing.T) {
+	samples := selfPlaySamples(t, 50)
+	baseline := 0.0
+	for _, s := range samples {
+		baseline += float64(s.Outcome*s.Outcome) / float64(len(samples))
+	}
+	baseline = math.Sqrt(baseline)
+
+	var errs []float64
+	e, rmse := TrainPatternEval(samples, WithTrainEpochs(20), WithTrainProgress(func(_ int, rmse float64) {
+		errs = append(errs, rmse)
+	}))
+	if len(errs) != 20 || math.Abs(errs[0]-baseline) > 1e-6 {
+		t.Fatalf("progress, want 20 epochs from %v, got %v", baseline, errs)
+	}
+	for i := 1; i < len(errs); i++ {
+		if errs[i] > errs[i-1] {
+			t.Errorf("error of epoch %d, want no more than %v, got %v", i+1, errs[i-1], errs[i])
+		}
+	}
+	if rmse >= 0.8*baseline {
+		t.Errorf("rmse, want below %v, got %v", 0.8*baseline, rmse)
+	}
+
+	var buf bytes.Buffer
+	n, err := e.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(len(patternMagic) + patternStages*patternWeights*4); n != want || int64(buf.Len()) != n {
+		t.Errorf("WriteTo(), want %v bytes, got %v, %v written", want, n, buf.Len())
+	}
+	if n, err := e.WriteTo(&failingWriter{limit: 100}); err == nil || n != 100 {
+		t.Errorf("WriteTo() failing after 100 bytes, want (100, error), got (%v, %v)", n, err)
+	}
+	read, err := ReadPatternEval(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range samples[:10] {
+		if got, want := read.Evaluate(s.Position), e.Evaluate(s.Position); got != want {
+			t.Errorf("Evaluate() after ReadPatternEval, want: %v, got %v", want, got)
+		}
+	}
+	if _, err := ReadPatternEval(bytes.NewReader([]byte("RVPE\x00"))); err == nil {
+		t.Error("ReadPatternEval() of a truncated file, want an error")
+	}
+
+	p := NewPlayer(1, WithPlayerType(Computer), WithLevel(1), WithEvaluation(e.Evaluate))
+	g := NewGameBoard(*p, *NewPlayer(2))
+	if move, err := g.P1().ChooseMove(g); err != nil || !g.P1().CanMove(move) {
+		t.Errorf("ChooseMove() with WithEvaluation, want a legal move, got (%v, %v)", move, err)
+	}
+}
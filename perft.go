@@ -0,0 +1,27 @@
+package reversi
+
+import "math/bits"
+
+// Perft counts the positions reached from p after depth plies, to check move
+// generation against known values. A pass is a ply of its own, and a game
+// that ends before depth counts as one leaf.
+func Perft(p Position, depth int) uint64 {
+	if depth == 0 {
+		return 1
+	}
+	moves := p.Moves()
+	if moves == 0 {
+		if p.Pass().Moves() == 0 {
+			return 1
+		}
+		return Perft(p.Pass(), depth-1)
+	}
+	if depth == 1 {
+		return uint64(bits.OnesCount64(moves))
+	}
+	var n uint64
+	for ; moves != 0; moves &= moves - 1 {
+		n += Perft(p.play(bits.TrailingZeros64(moves)), depth-1)
+	}
+	return n
+}
@@ -0,0 +1,104 @@
+package reversi
+
+import (
+	"fmt"
+	"math/bits"
+	"testing"
+)
+
+func TestPerft(t *testing.T) {
+	// Known counts from the starting position, passes included.
+	tests := []struct {
+		depth int
+		want  uint64
+	}{
+		{1, 4},
+		{2, 12},
+		{3, 56},
+		{4, 244},
+		{5, 1396},
+		{6, 8200},
+		{7, 55092},
+		{8, 390216},
+		{9, 3005288},
+		{10, 24571284},
+		{11, 212258800},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.depth), func(t *testing.T) {
+			if testing.Short() && tt.depth > 8 {
+				t.Skip("skipping deep perft in short mode")
+			}
+			if got := Perft(startPosition(), tt.depth); got != tt.want {
+				t.Errorf("Perft(start, %v), want: %v, got %v", tt.depth, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPerftPasses(t *testing.T) {
+	// O to move cannot flank the corner disc of X, so it has to pass, then X
+	// plays c1 or a3.
+	g := mustParsePosition(t, `
+		XO------
+		O-------
+		--------
+		--------
+		--------
+		--------
+		--------
+		--------
+		O`)
+	p := g.Position(2)
+	if p.Moves() != 0 || p.Pass().Moves() == 0 {
+		t.Fatalf("want a position where only the opponent can move, got %v", g)
+	}
+	tests := []struct {
+		depth int
+		want  uint64
+	}{
+		{1, 1},
+		{2, 2},
+	}
+	for _, tt := range tests {
+		if got := Perft(p, tt.depth); got != tt.want {
+			t.Errorf("Perft(pass, %v), want: %v, got %v", tt.depth, tt.want, got)
+		}
+	}
+}
+
+// FuzzMoveGenerators plays random games and checks the bitboard move
+// generator against the cell scan, moves and flips alike.
+func FuzzMoveGenerators(f *testing.F) {
+	for seed := int64(0); seed < 8; seed++ {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		playRandomGame(seed, func(g *GameBoard) {
+			for token := 1; token <= 2; token++ {
+				want := g.scanPossibleMoves(token)
+				if got := g.PossibleMoves(token); !equalMapUnorderedSlice(got, want) {
+					t.Fatalf("seed %v turn %v: PossibleMoves(%v), want: %v, got %v", seed, g.turn, token, want, got)
+				}
+				p := g.Position(token)
+				moves := p.Moves()
+				if got := bits.OnesCount64(moves); got != len(want) {
+					t.Fatalf("seed %v turn %v: Moves(%v) has %v moves, want %v", seed, g.turn, token, got, len(want))
+				}
+				for m, flipped := range want {
+					sq := m.Y*Width + m.X
+					if moves&(1<<sq) == 0 {
+						t.Fatalf("seed %v turn %v: Moves(%v) misses %v", seed, g.turn, token, m)
+					}
+					var mask uint64
+					for _, q := range flipped {
+						mask |= squareBit(q)
+					}
+					if got := flips(p.Own, p.Opp, sq); got != mask {
+						t.Fatalf("seed %v turn %v: flips(%v) of %v, want: %x, got %x", seed, g.turn, token, m, mask, got)
+					}
+				}
+			}
+		})
+	})
+}
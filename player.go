@@ -0,0 +1,359 @@
+package reversi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlayerType tells how a Player picks its moves.
+type PlayerType int
+
+const (
+	Unknown PlayerType = iota
+	Human
+	Computer
+)
+
+const (
+	MinLevel     = 1
+	MaxLevel     = 5
+	DefaultLevel = 3
+)
+
+// levels maps each Computer level to its search budget.
+var levels = map[int][]SearchCfgFunc{
+	1: {WithDepth(1)},
+	2: {WithDepth(2)},
+	3: {WithDepth(4), WithTimeLimit(time.Second)},
+	4: {WithDepth(6), WithTimeLimit(2 * time.Second)},
+	5: {WithDepth(10), WithTimeLimit(5 * time.Second)},
+}
+
+// Engine decides the moves of Computer players.
+type Engine interface {
+	// BestMove returns the move to play in p. It fails if the side to move
+	// has to pass.
+	BestMove(p Position) (Point, error)
+}
+
+// Player is one side of a game. The token (1 or 2) is the value the player's
+// discs take on the board.
+type Player struct {
+	id            uuid.UUID
+	token         int
+	name          string
+	score         int
+	possibleMoves map[Point][]Point
+	playerType    PlayerType
+	level         int
+	engine        Engine
+	// customEngine is set when engine came from WithEngine, and eval holds
+	// the evaluation of WithEvaluation, if any.
+	customEngine bool
+	eval         Evaluator
+	// antiEngine plays the FewestDiscs games of the search preset of the
+	// level. It is only made once needed.
+	antiEngine Engine
+	book       *Book
+	bookMoves  int
+	surrender  bool
+}
+
+type PlayerCfg struct {
+	id         uuid.UUID
+	name       string
+	playerType PlayerType
+	level      int
+	engine     Engine
+	eval       Evaluator
+	book       *Book
+	bookMoves  int
+}
+
+type PlayerCfgFunc func(playerCfg *PlayerCfg)
+
+func WithID(id uuid.UUID) PlayerCfgFunc {
+	return func(playerCfg *PlayerCfg) {
+		playerCfg.id = id
+	}
+}
+
+func WithName(name string) PlayerCfgFunc {
+	return func(playerCfg *PlayerCfg) {
+		playerCfg.name = name
+	}
+}
+
+func WithPlayerType(playerType PlayerType) PlayerCfgFunc {
+	return func(playerCfg *PlayerCfg) {
+		playerCfg.playerType = playerType
+	}
+}
+
+// WithLevel sets the strength of a Computer player, from MinLevel to
+// MaxLevel. Out of range levels fall back to DefaultLevel.
+func WithLevel(level int) PlayerCfgFunc {
+	return func(playerCfg *PlayerCfg) {
+		playerCfg.level = level
+	}
+}
+
+// WithEngine makes a Computer player use engine instead of the search
+// preset of its level. The engine plays every game of the player, including
+// FewestDiscs ones, so it should be set up for the objective it meets, e.g.
+// with WithMCTSObjective or WithSearchObjective.
+func WithEngine(engine Engine) PlayerCfgFunc {
+	return func(playerCfg *PlayerCfg) {
+		playerCfg.engine = engine
+	}
+}
+
+// WithEvaluation makes a Computer player search with eval, a trained
+// PatternEval for instance, at the depth and time of its level. The search
+// uses eval under either objective, so in FewestDiscs games eval should
+// score for the fewest discs.
+func WithEvaluation(eval Evaluator) PlayerCfgFunc {
+	return func(playerCfg *PlayerCfg) {
+		playerCfg.eval = eval
+	}
+}
+
+// WithOpeningBook makes a Computer player play from book while the game is
+// within its first moves moves and the position is in the book. Games set up
+// from a layout, a handicap or a position string are not played from book.
+func WithOpeningBook(book *Book, moves int) PlayerCfgFunc {
+	return func(playerCfg *PlayerCfg) {
+		playerCfg.book = book
+		playerCfg.bookMoves = moves
+	}
+}
+
+// NewPlayer creates a player owning the given token. Without options the
+// player is a Human with a random ID and a default name such as "P1" or "C2".
+func NewPlayer(token int, cfgFuncs ...PlayerCfgFunc) *Player {
+	var config PlayerCfg
+	for _, cfgFunc := range cfgFuncs {
+		cfgFunc(&config)
+	}
+
+	playerType := Human
+	if config.playerType != Unknown {
+		playerType = config.playerType
+	}
+
+	var id uuid.UUID
+	if config.id == uuid.Nil {
+		id = uuid.New()
+	} else {
+		id = config.id
+	}
+
+	var name string
+	if len(config.name) > 0 {
+		name = config.name
+	} else {
+		if playerType == Human {
+			name = "P"
+		} else {
+			name = "C"
+		}
+		name += strconv.Itoa(token)
+	}
+
+	p := &Player{
+		id:            id,
+		token:         token,
+		name:          name,
+		score:         2,
+		possibleMoves: make(map[Point][]Point),
+		playerType:    playerType,
+		surrender:     false,
+	}
+	if playerType == Computer {
+		p.level = config.level
+		if p.level < MinLevel || p.level > MaxLevel {
+			p.level = DefaultLevel
+		}
+		p.engine, p.customEngine, p.eval = config.engine, config.engine != nil, config.eval
+		if p.engine == nil {
+			p.engine = NewSearcher(p.searchCfg()...)
+		}
+		p.book, p.bookMoves = config.book, config.bookMoves
+	}
+	return p
+}
+
+// ID returns the unique ID of the player.
+func (p *Player) ID() uuid.UUID {
+	return p.id
+}
+
+// Token returns the board value of the player's discs.
+func (p *Player) Token() int {
+	return p.token
+}
+
+// Name returns the display name of the player.
+func (p *Player) Name() string {
+	return p.name
+}
+
+// Score returns the number of discs the player has on the board.
+func (p *Player) Score() int {
+	return p.score
+}
+
+// Type returns whether the player is a Human or a Computer.
+func (p *Player) Type() PlayerType {
+	return p.playerType
+}
+
+// Level returns the strength of a Computer player, or 0 for a Human.
+func (p *Player) Level() int {
+	return p.level
+}
+
+// Surrendered reports whether the player has given up the game.
+func (p *Player) Surrendered() bool {
+	return p.surrender
+}
+
+// PossibleMoves returns the legal moves of the player in row-major order.
+func (p *Player) PossibleMoves() []Point {
+	res := make([]Point, 0, len(p.possibleMoves))
+	for point := range p.possibleMoves {
+		res = append(res, point)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Y == res[j].Y {
+			return res[i].X < res[j].X
+		}
+		return res[i].Y < res[j].Y
+	})
+	return res
+}
+
+// CanMove reports whether point is a legal move for the player.
+func (p *Player) CanMove(point Point) bool {
+	_, ok := p.possibleMoves[point]
+	return ok
+}
+
+// ChooseMove picks the next move of the player on g. Human players are asked
+// on the console, Computer players decide on their own.
+func (p *Player) ChooseMove(g *GameBoard) (Point, error) {
+	if len(p.possibleMoves) == 0 {
+		return Point{}, errors.New("no possible moves")
+	}
+
+	switch p.playerType {
+	case Human:
+		return p.humanChooseMove(g.Size())
+	case Computer:
+		return p.computerChooseMove(g)
+	default:
+		return Point{}, errors.New("unexpected error in ChooseMove")
+	}
+}
+
+func (p *Player) computerChooseMove(g *GameBoard) (Point, error) {
+	if !g.standard() {
+		return p.greedyChooseMove(g.Size(), g.Objective())
+	}
+	pos := g.Position(p.token)
+	if g.Objective() == FewestDiscs {
+		// The book only knows MostDiscs lines. Custom engines are set up
+		// for their objective by the caller.
+		if p.customEngine {
+			return p.engine.BestMove(pos)
+		}
+		if p.antiEngine == nil {
+			p.antiEngine = NewSearcher(append(p.searchCfg(), WithSearchObjective(FewestDiscs))...)
+		}
+		return p.antiEngine.BestMove(pos)
+	}
+	if p.book != nil && g.fromStart() && g.playedMoves() < p.bookMoves {
+		if move, _, ok := p.book.Lookup(pos); ok {
+			return move, nil
+		}
+	}
+	return p.engine.BestMove(pos)
+}
+
+// searchCfg returns the options of the search preset of the level, with the
+// evaluation of WithEvaluation if set.
+func (p *Player) searchCfg() []SearchCfgFunc {
+	cfg := append([]SearchCfgFunc{}, levels[p.level]...)
+	if p.eval != nil {
+		cfg = append(cfg, WithEvaluator(p.eval))
+	}
+	return cfg
+}
+
+// greedyChooseMove picks the move flipping the most discs, taking a corner
+// whenever it can. Under FewestDiscs it flips the fewest and keeps off the
+// corners instead. It stands in for the engines on boards they cannot play
+// on.
+func (p *Player) greedyChooseMove(size int, objective Objective) (Point, error) {
+	var best Point
+	found, bestScore := false, 0
+	for _, point := range p.PossibleMoves() {
+		score := greedyScore(point, len(p.possibleMoves[point]), size, objective)
+		if !found || score > bestScore {
+			best, bestScore, found = point, score, true
+		}
+	}
+	if !found {
+		return Point{}, fmt.Errorf("unexpected error: possibleMoves of %v is empty", p.name)
+	}
+	return best, nil
+}
+
+// greedyScore scores a move flipping flips discs for greedyChooseMove.
+func greedyScore(point Point, flips, size int, objective Objective) int {
+	score := flips
+	if (point.X == 0 || point.X == size-1) && (point.Y == 0 || point.Y == size-1) {
+		score += size * size
+	}
+	return score * objective.sign()
+}
+
+func (p *Player) randomChooseMove() (Point, error) {
+	for point := range p.possibleMoves {
+		return point, nil
+	}
+	return Point{}, fmt.Errorf("unexpected error: possibleMoves of %v is empty", p.name)
+}
+
+func (p *Player) humanChooseMove(size int) (Point, error) {
+	for i := 0; i < 3; i++ {
+		var input Notation
+		fmt.Printf("%v: Choose a cell for your disk (e.g., c2, h3): ", p.name)
+		fmt.Scanln(&input)
+
+		if len(input) == 0 {
+			return p.randomChooseMove()
+		}
+
+		point, err := input.ToPointOn(size)
+		if err != nil {
+			fmt.Println(err.Error())
+			continue
+		}
+
+		_, ok := p.possibleMoves[point]
+		if !ok {
+			fmt.Println("Invalid move, try again.")
+		}
+
+		return point, nil
+	}
+
+	fmt.Println("Too many trails! The game will randomly choose a cell.")
+	return p.randomChooseMove()
+}
@@ -0,0 +1,169 @@
+package reversi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strings"
+)
+
+// Position is a compact view of the discs from the side to move: Own holds
+// the discs of the player to move and Opp those of the opponent, one bit per
+// cell at y*Width+x. It is the representation the engines search on.
+type Position struct {
+	Own uint64
+	Opp uint64
+}
+
+// Position returns the discs of g seen from the player holding token. It is
+// empty on boards other than the standard one.
+func (g GameBoard) Position(token int) Position {
+	return Position{Own: g.bb.own(token), Opp: g.bb.oppo(token)}
+}
+
+// PositionFromBoard builds the Position of the player holding token from
+// cells laid out like GameBoard, indexed [y][x] with 0, 1 or 2.
+func PositionFromBoard(board [][]int, token int) (Position, error) {
+	if token != 1 && token != 2 {
+		return Position{}, fmt.Errorf("invalid token %d", token)
+	}
+	if len(board) != Height {
+		return Position{}, fmt.Errorf("board must have %d rows, got %d", Height, len(board))
+	}
+	for y, row := range board {
+		if len(row) != Width {
+			return Position{}, fmt.Errorf("row %d must have %d cells, got %d", y+1, Width, len(row))
+		}
+		for _, cell := range row {
+			if cell < 0 || cell > 2 {
+				return Position{}, errors.New("cells must be 0, 1 or 2")
+			}
+		}
+	}
+	bb := newBitboard(board)
+	return Position{Own: bb.own(token), Opp: bb.oppo(token)}, nil
+}
+
+// Moves returns the legal moves of the side to move as a bit mask.
+func (p Position) Moves() uint64 {
+	return legalMoves(p.Own, p.Opp)
+}
+
+// Play returns the position after the side to move plays on point, seen from
+// the opponent. The move is not validated.
+func (p Position) Play(point Point) Position {
+	return p.play(point.Y*Width + point.X)
+}
+
+func (p Position) play(sq int) Position {
+	f := flips(p.Own, p.Opp, sq)
+	return Position{Own: p.Opp &^ f, Opp: p.Own | f | 1<<sq}
+}
+
+// Pass returns the position with the turn handed to the opponent.
+func (p Position) Pass() Position {
+	return Position{Own: p.Opp, Opp: p.Own}
+}
+
+// Empties returns the number of empty cells.
+func (p Position) Empties() int {
+	return Width*Height - bits.OnesCount64(p.Own|p.Opp)
+}
+
+// DiscDiff returns own discs minus opponent discs.
+func (p Position) DiscDiff() int {
+	return bits.OnesCount64(p.Own) - bits.OnesCount64(p.Opp)
+}
+
+// GameOver reports whether neither side can move.
+func (p Position) GameOver() bool {
+	return p.Moves() == 0 && p.Pass().Moves() == 0
+}
+
+// positionCells maps the cell values of a GameBoard to their characters in a
+// position string. Blocked cells are written as blockedCell.
+const (
+	positionCells = "-XOYZ"
+	blockedCell   = '#'
+)
+
+// positionCell returns the value of the cell written as c, or false if c is
+// not a cell.
+func positionCell(c byte) (int, bool) {
+	if c == blockedCell {
+		return Blocked, true
+	}
+	cell := strings.IndexByte(positionCells, c)
+	return cell, cell >= 0
+}
+
+// String returns the position string of g: the cells row by row from a1 to
+// the last cell as "-" for empty, "X" for token 1, "O" for token 2, "Y" and
+// "Z" for tokens 3 and 4 and "#" for blocked, then a space and the token to
+// move, e.g.
+//
+//	---------------------------XO------OX--------------------------- X
+//
+// is the starting position with token 1 to move. The board size follows from
+// the number of cells.
+func (g GameBoard) String() string {
+	var sb strings.Builder
+	for _, row := range g.board {
+		for _, cell := range row {
+			if cell == Blocked {
+				sb.WriteByte(blockedCell)
+				continue
+			}
+			sb.WriteByte(positionCells[cell])
+		}
+	}
+	sb.WriteByte(' ')
+	sb.WriteByte(positionCells[g.toMove])
+	return sb.String()
+}
+
+// ParsePosition sets up a game of p1 and p2 from a position string as written
+// by String. Letters may be lower case and the cells may be split by spaces
+// or new lines, one row per line for instance. The side to move and the
+// board size in s override WithP1First and WithSize. Tokens 3 and 4 need the
+// players of WithMorePlayers. The game has no history and the turn counter
+// follows from the number of discs.
+func ParsePosition(s string, p1, p2 Player, cfgFuncs ...GameCfgFunc) (*GameBoard, error) {
+	fields := strings.Fields(strings.ToUpper(s))
+	if len(fields) < 2 {
+		return nil, errors.New("position needs cells and a side to move")
+	}
+	cells, side := strings.Join(fields[:len(fields)-1], ""), fields[len(fields)-1]
+	size := int(math.Sqrt(float64(len(cells))))
+	if size*size != len(cells) || !validSize(size) {
+		return nil, fmt.Errorf("position must have the cells of a board from %dx%d to %dx%d, got %d", MinSize, MinSize, MaxSize, MaxSize, len(cells))
+	}
+	cfg := newGameConfig(cfgFuncs...)
+	players := 2 + len(cfg.more)
+	toMove := strings.Index(positionCells, side)
+	if len(side) != 1 || toMove < 1 || toMove > players {
+		return nil, fmt.Errorf("invalid side to move %q", side)
+	}
+
+	board := emptyBoard(size)
+	discs := 0
+	for y := range board {
+		for x := range board[y] {
+			c := cells[y*size+x]
+			cell, ok := positionCell(c)
+			if !ok || cell > players {
+				return nil, fmt.Errorf("invalid cell %q at %c%d", c, 'a'+x, y+1)
+			}
+			board[y][x] = cell
+			if cell > 0 {
+				discs++
+			}
+		}
+	}
+
+	cfg.p1First, cfg.size = toMove == 1, size
+	g := newGame(board, toMove, p1, p2, cfg)
+	g.turn = max(1, discs-3)
+	return g, nil
+}
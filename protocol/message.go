@@ -0,0 +1,270 @@
+// Package protocol defines the messages exchanged over the WebSocket between
+// the server and its clients, browsers and bots alike. Every message is a
+// JSON object with an action and a payload, see Message and ClientMessage.
+package protocol
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/oscarhkli/reversi"
+)
+
+type MessageType string
+
+const (
+	SendMessage       MessageType = "SEND_MESSAGE"
+	RoomUpdated       MessageType = "ROOM_UPDATED"
+	JoinRoom          MessageType = "JOIN_ROOM"
+	LeaveRoom         MessageType = "LEAVE_ROOM"
+	StartGame         MessageType = "START_GAME"
+	GameError         MessageType = "GAME_ERROR"
+	GameState         MessageType = "GAME_STATE"
+	MakeMove          MessageType = "MAKE_MOVE"
+	GameResult        MessageType = "GAME_RESULT"
+	RegisterResponse  MessageType = "REGISTER_RESPONSE"
+	JoinRoomResponse  MessageType = "JOIN_ROOM_RESPONSE"
+	LeaveRoomResponse MessageType = "LEAVE_ROOM_RESPONSE"
+	BotUpdated        MessageType = "BOT_UPDATED"
+	Challenge         MessageType = "CHALLENGE"
+	AcceptChallenge   MessageType = "ACCEPT_CHALLENGE"
+	DeclineChallenge  MessageType = "DECLINE_CHALLENGE"
+)
+
+// ClientPayloads maps the actions a client may send to their payloads.
+var ClientPayloads = map[MessageType]any{
+	SendMessage:      "",
+	JoinRoom:         JoinRoomPayload{},
+	LeaveRoom:        LeaveRoomPayload{},
+	StartGame:        StartGamePayload{},
+	MakeMove:         MakeMovePayload{},
+	Challenge:        ChallengeRequestPayload{},
+	AcceptChallenge:  ChallengeReplyPayload{},
+	DeclineChallenge: ChallengeReplyPayload{},
+}
+
+// ServerPayloads maps the actions the server sends to their payloads.
+var ServerPayloads = map[MessageType]any{
+	SendMessage:       "",
+	RoomUpdated:       RoomUpdatedPayload{},
+	GameError:         "",
+	GameState:         GameStatePayload{},
+	GameResult:        GameResultPayload{},
+	RegisterResponse:  RegisterResponsePayload{},
+	JoinRoomResponse:  JoinRoomPayload{},
+	LeaveRoomResponse: LeaveRoomPayload{},
+	BotUpdated:        BotPayload{},
+	Challenge:         ChallengePayload{},
+}
+
+// Message is sent by the server. Message holds the payload of Action, see
+// ServerPayloads.
+type Message struct {
+	Action  MessageType `json:"action"`
+	Message any         `json:"message"`
+	Target  string      `json:"target"`
+	Sender  *Sender     `json:"sender"`
+}
+
+// Sender is the client a chat message comes from.
+type Sender struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// Encode returns the JSON form of m.
+func (m *Message) Encode() []byte {
+	json, err := json.Marshal(m)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return json
+}
+
+type RegisterResponsePayload struct {
+	ID    string               `json:"id"`
+	Name  string               `json:"name"`
+	Rooms []RoomUpdatedPayload `json:"rooms"`
+	// Bots lists the bots connected to the server.
+	Bots []BotPayload `json:"bots"`
+}
+
+// BotPayload is a bot connected to the server. Action is "JOINED" when it
+// connects and "LEFT" when it goes.
+type BotPayload struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Action string `json:"action,omitempty"`
+}
+
+// ChallengeRequestPayload challenges the bot with ID BotID to a game in
+// the room of the challenger, started with Game. The room and players of
+// Game are filled in by the server.
+type ChallengeRequestPayload struct {
+	BotID string           `json:"botID"`
+	Game  StartGamePayload `json:"game"`
+}
+
+// ChallengePayload asks a bot to play the challenger in the given room. The
+// bot answers with ACCEPT_CHALLENGE or DECLINE_CHALLENGE and the ID.
+type ChallengePayload struct {
+	ID             string           `json:"id"`
+	RoomUUID       string           `json:"roomUUID"`
+	RoomName       string           `json:"roomName"`
+	ChallengerID   string           `json:"challengerID"`
+	ChallengerName string           `json:"challengerName"`
+	Game           StartGamePayload `json:"game"`
+}
+
+// ChallengeReplyPayload answers the challenge with the given ID. Reason
+// tells the challenger why a challenge was declined.
+type ChallengeReplyPayload struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ClientMessage is sent by a client. Message holds the payload of Action,
+// see ClientPayloads. The server fills in Sender.
+type ClientMessage struct {
+	Action  MessageType `json:"action"`
+	Message any         `json:"message"`
+	Target  string      `json:"target"`
+	Sender  *Sender     `json:"-"`
+}
+
+type JoinRoomPayload struct {
+	RoomUUID string `json:"roomUUID"`
+	Name     string `json:"name"`
+}
+
+type LeaveRoomPayload struct {
+	RoomUUID string `json:"roomUUID"`
+}
+
+type StartGamePayload struct {
+	RoomUUID string `json:"roomUUID"`
+	// Position is the position string to start from instead of the
+	// starting position.
+	Position string `json:"position,omitempty"`
+	// Size is the number of rows and columns of the board, 8 if not set.
+	Size int `json:"size,omitempty"`
+	// Variant picks the starting layout, see the Variant constants.
+	Variant Variant `json:"variant,omitempty"`
+	// Blocked lists cells to block on top of the variant.
+	Blocked []reversi.Point `json:"blocked,omitempty"`
+	// AntiReversi makes the player with fewer discs win.
+	AntiReversi bool `json:"antiReversi,omitempty"`
+	// Handicap gives a player corner discs before the first move.
+	Handicap *HandicapPayload `json:"handicap,omitempty"`
+	// Players is the number of players seated, from 2 to 4. Games of more
+	// than two players are played on a 10x10 board unless Size says otherwise.
+	Players int `json:"players,omitempty"`
+	// Analysis adds the Analysis of every position to the game state.
+	Analysis bool `json:"analysis,omitempty"`
+	// Hints sets the help players get, HintMoves if not set.
+	Hints HintLevel `json:"hints,omitempty"`
+	// Computer seats a computer player in the last seat, so fewer clients
+	// are needed.
+	Computer *ComputerPayload `json:"computer,omitempty"`
+}
+
+// ComputerPayload sets up a computer player of the given Level, from
+// reversi.MinLevel to reversi.MaxLevel, reversi.DefaultLevel if not set. It
+// waits at least DelayMs milliseconds before each move, half a second if not
+// set. With Engine set, the external engine of the server plays
+// instead of the built-in search.
+type ComputerPayload struct {
+	Level   int  `json:"level,omitempty"`
+	DelayMs int  `json:"delayMs,omitempty"`
+	Engine  bool `json:"engine,omitempty"`
+}
+
+// HintLevel is how much help a room gives the player to move.
+type HintLevel string
+
+const (
+	// HintNone leaves the possible moves unmarked, for serious games.
+	HintNone HintLevel = "none"
+	// HintMoves marks the possible moves.
+	HintMoves HintLevel = "moves"
+	// HintScored marks the possible moves with their scores and the best
+	// ones.
+	HintScored HintLevel = "scored"
+)
+
+// HandicapPayload gives the player with ID Player a disc on Corners corners,
+// from 1 to 4.
+type HandicapPayload struct {
+	Player  string `json:"player"`
+	Corners int    `json:"corners"`
+}
+
+// Variant is a starting layout a room can play.
+type Variant string
+
+const (
+	// Standard starts from the four discs in the centre.
+	Standard Variant = "standard"
+	// Balanced starts from a random opening that is close to even.
+	Balanced Variant = "balanced"
+	// Obstacles blocks random cells, mirrored through the centre.
+	Obstacles Variant = "obstacles"
+)
+
+type RoomUpdatedPayload struct {
+	RoomUUID string `json:"roomUUID"`
+	Action   string `json:"action"`
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+}
+
+type PlayerPayload struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Token         int             `json:"token"`
+	Score         int             `json:"score"`
+	PossibleMoves []reversi.Point `json:"possibleMoves"`
+	// Level is the strength of a computer player, 0 for a client.
+	Level int `json:"level,omitempty"`
+	// Hints scores the possible moves of the player to move when the room
+	// gives scored hints.
+	Hints []reversi.Hint `json:"hints,omitempty"`
+}
+
+type GameStatePayload struct {
+	P1 PlayerPayload `json:"p1"`
+	P2 PlayerPayload `json:"p2"`
+	// Players holds every player in the order of their tokens, P1 and P2
+	// included.
+	Players       []PlayerPayload `json:"players"`
+	Round         int             `json:"round"`
+	Turn          int             `json:"turn"`
+	CurrentPlayer string          `json:"currentPlayer"`
+	Size          int             `json:"size"`
+	Board         [][]int         `json:"board"`
+	Hash          string          `json:"hash"`
+	Opening       string          `json:"opening"`
+	Position      string          `json:"position"`
+	AntiReversi   bool            `json:"antiReversi"`
+	Hints         HintLevel       `json:"hints"`
+	// Handicap is the handicap the game started with, if any.
+	Handicap *HandicapPayload `json:"handicap,omitempty"`
+	// Analysis is set when the game was started with analysis.
+	Analysis *reversi.Analysis `json:"analysis,omitempty"`
+}
+
+// GameResultPayload announces the end of a game. Winner is the ID of the
+// winner, empty for a draw.
+type GameResultPayload struct {
+	Winner   string           `json:"winner"`
+	P1       PlayerPayload    `json:"p1"`
+	P2       PlayerPayload    `json:"p2"`
+	Players  []PlayerPayload  `json:"players"`
+	Handicap *HandicapPayload `json:"handicap,omitempty"`
+}
+
+type MakeMovePayload struct {
+	RoomUUID string        `json:"roomUUID"`
+	Point    reversi.Point `json:"point"`
+}
@@ -0,0 +1,131 @@
+package protocol
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// enums lists the values of the string types with a fixed set of values.
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(HintLevel("")): {string(HintNone), string(HintMoves), string(HintScored)},
+	reflect.TypeOf(Variant("")):   {string(Standard), string(Balanced), string(Obstacles)},
+}
+
+var textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// Schema returns a JSON Schema of the messages, built from ClientPayloads and
+// ServerPayloads. Its definitions ClientMessage and ServerMessage describe
+// every message a client may send and receive.
+func Schema() ([]byte, error) {
+	defs := make(map[string]any)
+	defs["ClientMessage"] = messagesSchema(ClientPayloads, false, defs)
+	defs["ServerMessage"] = messagesSchema(ServerPayloads, true, defs)
+	return json.MarshalIndent(map[string]any{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "Reversi WebSocket messages",
+		"$defs":   defs,
+		"anyOf": []any{
+			map[string]any{"$ref": "#/$defs/ClientMessage"},
+			map[string]any{"$ref": "#/$defs/ServerMessage"},
+		},
+	}, "", "  ")
+}
+
+// messagesSchema describes a message of every action of payloads, in the
+// order of the actions.
+func messagesSchema(payloads map[MessageType]any, server bool, defs map[string]any) map[string]any {
+	actions := make([]string, 0, len(payloads))
+	for action := range payloads {
+		actions = append(actions, string(action))
+	}
+	sort.Strings(actions)
+
+	var oneOf []any
+	for _, action := range actions {
+		properties := map[string]any{
+			"action":  map[string]any{"const": action},
+			"message": typeSchema(reflect.TypeOf(payloads[MessageType(action)]), defs),
+			"target":  map[string]any{"type": "string"},
+		}
+		if server {
+			properties["sender"] = typeSchema(reflect.TypeOf(&Sender{}), defs)
+		}
+		oneOf = append(oneOf, map[string]any{
+			"type":       "object",
+			"properties": properties,
+			"required":   []string{"action", "message"},
+		})
+	}
+	return map[string]any{"oneOf": oneOf}
+}
+
+// typeSchema describes the JSON form of t, adding the structs it refers to
+// to defs.
+func typeSchema(t reflect.Type, defs map[string]any) map[string]any {
+	if t.Implements(textMarshaler) {
+		return map[string]any{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return map[string]any{"anyOf": []any{typeSchema(t.Elem(), defs), map[string]any{"type": "null"}}}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		if values, ok := enums[t]; ok {
+			return map[string]any{"type": "string", "enum": values}
+		}
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		// Nil slices are written as null.
+		return map[string]any{"type": []string{"array", "null"}, "items": typeSchema(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), defs)}
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := defs[name]; !ok {
+			// Placeholder for structs referring to themselves.
+			defs[name] = nil
+			defs[name] = structSchema(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + name}
+	default:
+		return map[string]any{}
+	}
+}
+
+// structSchema describes the JSON object of the struct type t. Fields
+// without omitempty are required.
+func structSchema(t reflect.Type, defs map[string]any) map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = typeSchema(f.Type, defs)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
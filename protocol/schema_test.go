@@ -0,0 +1,49 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite schema.json")
+
+func TestSchemaUpToDate(t *testing.T) {
+	got, err := Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+	if *update {
+		if err := os.WriteFile("schema.json", got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("schema.json is out of date, run: go test ./protocol -run TestSchemaUpToDate -update")
+	}
+}
+
+func TestSchemaDefinesPayloads(t *testing.T) {
+	b, err := Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Defs map[string]json.RawMessage `json:"$defs"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"ClientMessage", "ServerMessage", "StartGamePayload", "GameStatePayload", "ChallengePayload", "Point", "Hint", "Analysis"} {
+		if _, ok := schema.Defs[name]; !ok {
+			t.Errorf("schema has no definition of %v", name)
+		}
+	}
+}
@@ -0,0 +1,422 @@
+package reversi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ggfDate is the date layout of the DT property written by game servers.
+const ggfDate = "2006.01.02_15:04:05.MST"
+
+// GameInfo holds the details of a game record that the board does not know
+// about.
+type GameInfo struct {
+	Place       string
+	Date        time.Time
+	TimeControl string
+}
+
+// blackToken returns the token of the player called black in the standard
+// transcript formats: the one who moved first, unless the game was set up
+// from a GGF position with white to move.
+func (g GameBoard) blackToken() int {
+	if g.cfg.p1First {
+		return 1
+	}
+	return 2
+}
+
+// standardPoint maps p to the orientation of the standard Othello board,
+// where black starts on e4 and d5. Black starts on d4 and e5 here when token 1
+// moves first, see orientMoves.
+func (g GameBoard) standardPoint(p Point) Point {
+	if g.blackToken() == 1 {
+		return mirrorOn(p, g.cfg.size)
+	}
+	return p
+}
+
+// StandardNotation returns the notation of p, a point of g such as a move of
+// Stats, in the standard orientation of Transcript and ParseTranscript.
+func (g GameBoard) StandardNotation(p Point) (Notation, error) {
+	return g.standardPoint(p).ToNotationOn(g.cfg.size)
+}
+
+// Transcript returns the moves played so far in the standard orientation,
+// e.g. "f5d6c3". Passes are left out as they follow from the position.
+func (g GameBoard) Transcript() string {
+	var sb strings.Builder
+	for _, m := range g.history {
+		if m.Pass {
+			continue
+		}
+		n, _ := g.standardPoint(m.Point).ToNotationOn(g.cfg.size)
+		sb.WriteString(string(n))
+	}
+	return sb.String()
+}
+
+// GGF returns the game in Generic Game Format, with the player names, the
+// result and the details in info. The result is "?" while the game goes on.
+// A handicap is written as HA with the color receiving it and the number of
+// corners, e.g. HA[W2]. The format only knows two colors, so it is not meant
+// for games of more than two players.
+func (g GameBoard) GGF(info GameInfo) string {
+	black, white := g.players[0], g.players[1]
+	if g.blackToken() == 2 {
+		black, white = white, black
+	}
+
+	var sb strings.Builder
+	sb.WriteString("(;GM[Othello]")
+	if info.Place != "" {
+		fmt.Fprintf(&sb, "PC[%s]", ggfEscape(info.Place))
+	}
+	if !info.Date.IsZero() {
+		fmt.Fprintf(&sb, "DT[%s]", info.Date.Format(ggfDate))
+	}
+	fmt.Fprintf(&sb, "PB[%s]PW[%s]RE[%s]", ggfEscape(black.name), ggfEscape(white.name), g.ggfResult(black, white))
+	if info.TimeControl != "" {
+		fmt.Fprintf(&sb, "TI[%s]", ggfEscape(info.TimeControl))
+	}
+	fmt.Fprintf(&sb, "TY[%s]BO[%d %s %s]", g.ggfType(), g.cfg.size, g.ggfBoard(), g.ggfFirst())
+	if h := g.cfg.handicap; h.Corners > 0 {
+		color := "B"
+		if h.Token != black.token {
+			color = "W"
+		}
+		fmt.Fprintf(&sb, "HA[%s%d]", color, h.Corners)
+	}
+	for _, m := range g.history {
+		color := "B"
+		if m.Token != black.token {
+			color = "W"
+		}
+		n := Notation("pa")
+		if !m.Pass {
+			n, _ = g.standardPoint(m.Point).ToNotationOn(g.cfg.size)
+		}
+		fmt.Fprintf(&sb, "%s[%s]", color, n)
+	}
+	sb.WriteString(";)")
+	return sb.String()
+}
+
+// ggfBoard returns the starting position for the BO property in the standard
+// orientation, row by row with black as "*" and white as "O". Blocked cells
+// are written as in position strings.
+func (g GameBoard) ggfBoard() string {
+	var sb strings.Builder
+	n := g.cfg.size
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			p := g.standardPoint(Point{x, y})
+			switch cell, _ := positionCell(g.start[p.Y*n+p.X]); cell {
+			case 0:
+				sb.WriteByte('-')
+			case Blocked:
+				sb.WriteByte(blockedCell)
+			case g.blackToken():
+				sb.WriteByte('*')
+			default:
+				sb.WriteByte('O')
+			}
+		}
+	}
+	return sb.String()
+}
+
+// ggfFirst returns the side to move of the BO property, "*" unless white
+// moved first.
+func (g GameBoard) ggfFirst() string {
+	if first, _ := positionCell(g.start[len(g.start)-1]); first != g.blackToken() {
+		return "O"
+	}
+	return "*"
+}
+
+// ggfPosition turns the BO property into a position string with the side to
+// move as token 1, in the orientation of NewGameBoard. The board is mirrored
+// only when black moves first, see GameBoard.standardPoint.
+func ggfPosition(bo string) (string, bool, error) {
+	f := strings.Fields(bo)
+	if len(f) != 3 || f[2] != "*" && f[2] != "O" {
+		return "", false, fmt.Errorf("invalid starting position %q", bo)
+	}
+	n, err := strconv.Atoi(f[0])
+	if err != nil || !validSize(n) || len(f[1]) != n*n {
+		return "", false, fmt.Errorf("invalid starting position %q", bo)
+	}
+	blackFirst := f[2] == "*"
+	var sb strings.Builder
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			p := Point{x, y}
+			if blackFirst {
+				p = mirrorOn(p, n)
+			}
+			switch c := f[1][p.Y*n+p.X]; {
+			case c == '-' || c == blockedCell:
+				sb.WriteByte(c)
+			case c != '*' && c != 'O':
+				return "", false, fmt.Errorf("invalid cell %q in starting position", c)
+			case (c == '*') == blackFirst:
+				sb.WriteByte('X')
+			default:
+				sb.WriteByte('O')
+			}
+		}
+	}
+	sb.WriteString(" X")
+	return sb.String(), blackFirst, nil
+}
+
+// ggfType returns the TY property: the board size, followed by "a" for
+// FewestDiscs games.
+func (g GameBoard) ggfType() string {
+	t := strconv.Itoa(g.cfg.size)
+	if g.cfg.objective == FewestDiscs {
+		t += "a"
+	}
+	return t
+}
+
+// ggfResult returns the disc difference from black's view, negated under
+// FewestDiscs so that a positive result is always a win for black. A
+// resignation is marked with ":r" and counts as a loss by 64 discs.
+func (g GameBoard) ggfResult(black, white *Player) string {
+	switch {
+	case black.surrender:
+		return "-64.000:r"
+	case white.surrender:
+		return "+64.000:r"
+	case !g.EndGame():
+		return "?"
+	}
+	return fmt.Sprintf("%+.3f", float64(g.cfg.objective.sign()*(black.score-white.score)))
+}
+
+func ggfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "]", `\]`).Replace(s)
+}
+
+// ParseTranscript rebuilds a game from a transcript such as "f5d6c3" on the
+// board set up by cfgFuncs. The moves may be written for the orientation of
+// the standard Othello board or for this one. Passes are played when needed.
+func ParseTranscript(s string, cfgFuncs ...GameCfgFunc) (*GameBoard, error) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), cfgFuncs...)
+	moves, err := parseMoves(s, g.cfg.size)
+	if err != nil {
+		return nil, err
+	}
+	oriented := moves
+	if len(moves) > 0 && !g.CurrentPlayer().CanMove(moves[0]) {
+		oriented = make([]Point, len(moves))
+		for i, m := range moves {
+			oriented[i] = mirrorOn(m, g.cfg.size)
+		}
+	}
+	for i, p := range oriented {
+		if g.MustPass() {
+			g.Pass()
+		}
+		if _, err := g.Mark(p, *g.CurrentPlayer()); err != nil {
+			n, _ := moves[i].ToNotationOn(g.cfg.size)
+			return nil, fmt.Errorf("illegal move %v at move %d", n, i+1)
+		}
+	}
+	return g, nil
+}
+
+// ParseGGF rebuilds a game from a record in Generic Game Format. Player 1
+// takes the side moving first in the starting position, which is black
+// unless the record says otherwise.
+func ParseGGF(s string) (*GameBoard, GameInfo, error) {
+	var info GameInfo
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "(;") || !strings.HasSuffix(s, ";)") {
+		return nil, info, errors.New("invalid GGF record")
+	}
+	props, err := parseGGFProperties(s[2 : len(s)-2])
+	if err != nil {
+		return nil, info, err
+	}
+
+	var black, white, result string
+	// start is the position string of the BO property, if any.
+	var start string
+	size, blackFirst := Width, true
+	objective := MostDiscs
+	// handicap is the HA property, the color and the number of corners.
+	var handicap string
+	var g *GameBoard
+	setup := func() error {
+		if g != nil {
+			return nil
+		}
+		first, second := black, white
+		if !blackFirst {
+			first, second = white, black
+		}
+		p1, p2 := *NewPlayer(1, WithName(first)), *NewPlayer(2, WithName(second))
+		cfgFuncs := []GameCfgFunc{WithObjective(objective)}
+		if handicap != "" {
+			token := 1
+			if (handicap[0] == 'B') != blackFirst {
+				token = 2
+			}
+			corners, _ := strconv.Atoi(handicap[1:])
+			cfgFuncs = append(cfgFuncs, WithHandicap(token, corners))
+		}
+		if start == "" {
+			g = NewGameBoard(p1, p2, append(cfgFuncs, WithSize(size))...)
+			return nil
+		}
+		var err error
+		if g, err = ParsePosition(start, p1, p2, cfgFuncs...); err != nil {
+			return err
+		}
+		// Token 1 is to move, but it is only black if black moves first.
+		g.cfg.p1First = blackFirst
+		return nil
+	}
+	moves := 0
+	for _, prop := range props {
+		switch prop.name {
+		case "GM":
+			if !strings.EqualFold(prop.value, "Othello") {
+				return nil, info, fmt.Errorf("unsupported game %q", prop.value)
+			}
+		case "PC":
+			info.Place = prop.value
+		case "DT":
+			info.Date = parseGGFDate(prop.value)
+		case "TI":
+			info.TimeControl = prop.value
+		case "PB":
+			black = prop.value
+		case "PW":
+			white = prop.value
+		case "RE":
+			result = prop.value
+		case "TY":
+			t := strings.TrimSpace(prop.value)
+			if anti, ok := strings.CutSuffix(t, "a"); ok {
+				t, objective = anti, FewestDiscs
+			}
+			if size, err = strconv.Atoi(t); err != nil || !validSize(size) {
+				return nil, info, fmt.Errorf("unsupported board type %q", t)
+			}
+		case "HA":
+			handicap = strings.ToUpper(strings.TrimSpace(prop.value))
+			if !validGGFHandicap(handicap) {
+				return nil, info, fmt.Errorf("invalid handicap %q", prop.value)
+			}
+		case "BO":
+			if start, blackFirst, err = ggfPosition(prop.value); err != nil {
+				return nil, info, err
+			}
+		case "B", "W":
+			if err := setup(); err != nil {
+				return nil, info, err
+			}
+			moves++
+			token := 1
+			if (prop.name == "B") != blackFirst {
+				token = 2
+			}
+			if err := playGGFMove(g, token, prop.value); err != nil {
+				return nil, info, fmt.Errorf("move %d: %w", moves, err)
+			}
+		}
+	}
+	if err := setup(); err != nil {
+		return nil, info, err
+	}
+
+	if strings.HasSuffix(result, ":r") {
+		loser := g.players[0]
+		if strings.HasPrefix(result, "-") != blackFirst {
+			loser = g.players[1]
+		}
+		g.Surrender(loser)
+	}
+	return g, info, nil
+}
+
+// validGGFHandicap reports whether s is a color, B or W, followed by a number
+// of corners, e.g. "W2".
+func validGGFHandicap(s string) bool {
+	if len(s) < 2 || s[0] != 'B' && s[0] != 'W' {
+		return false
+	}
+	n, err := strconv.Atoi(s[1:])
+	return err == nil && n >= 1 && n <= MaxHandicap
+}
+
+type ggfProperty struct {
+	name, value string
+}
+
+// parseGGFProperties splits the body of a record into NAME[value] pairs.
+func parseGGFProperties(s string) ([]ggfProperty, error) {
+	var props []ggfProperty
+	for {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return props, nil
+		}
+		open := strings.IndexByte(s, '[')
+		if open <= 0 {
+			return nil, fmt.Errorf("invalid GGF property at %q", s)
+		}
+		var value strings.Builder
+		i := open + 1
+		for ; i < len(s) && s[i] != ']'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+			}
+			value.WriteByte(s[i])
+		}
+		if i == len(s) {
+			return nil, fmt.Errorf("unterminated GGF property %v", s[:open])
+		}
+		props = append(props, ggfProperty{strings.TrimSpace(s[:open]), value.String()})
+		s = s[i+1:]
+	}
+}
+
+// playGGFMove plays a move such as "f5" or "f5/1.50/2.01" for token. "pa"
+// is a pass.
+func playGGFMove(g *GameBoard, token int, value string) error {
+	if g.toMove != token {
+		return errors.New("not your turn")
+	}
+	n := strings.ToLower(strings.TrimSpace(strings.SplitN(value, "/", 2)[0]))
+	if n == "pa" {
+		return g.Pass()
+	}
+	p, err := Notation(n).ToPointOn(g.cfg.size)
+	if err != nil {
+		return fmt.Errorf("invalid move %q", n)
+	}
+	if _, err := g.Mark(g.standardPoint(p), *g.CurrentPlayer()); err != nil {
+		return fmt.Errorf("illegal move %v", n)
+	}
+	return nil
+}
+
+// parseGGFDate reads the DT property, written either in the ggfDate layout or
+// as seconds since the Unix epoch. Unknown formats give the zero time.
+func parseGGFDate(s string) time.Time {
+	if t, err := time.Parse(ggfDate, s); err == nil {
+		return t
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0).UTC()
+	}
+	return time.Time{}
+}
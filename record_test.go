@@ -0,0 +1,229 @@
+package reversi
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTranscript(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	for _, p := range []Point{{4, 2}, {3, 2}, {2, 2}} {
+		g.Mark(p, *g.CurrentPlayer())
+	}
+	if got, want := g.Transcript(), "d3e3f3"; got != want {
+		t.Errorf("Transcript(), want: %v, got %v", want, got)
+	}
+
+	for seed := int64(0); seed < 10; seed++ {
+		var g *GameBoard
+		playRandomGame(seed, func(h *GameBoard) { g = h })
+		s := g.Transcript()
+		if first := s[:2]; !strings.Contains("f5 d3 c4 e6", first) {
+			t.Errorf("seed %v: Transcript() opens with %v, want a standard opening", seed, first)
+		}
+		h, err := ParseTranscript(s)
+		if err != nil {
+			t.Fatalf("seed %v: ParseTranscript(%v), got error %v", seed, s, err)
+		}
+		if h.Hash() != g.Hash() || len(h.History()) != len(g.History()) || !h.EndGame() {
+			t.Errorf("seed %v: ParseTranscript(%v) does not rebuild the game", seed, s)
+		}
+	}
+}
+
+func TestTranscriptP2First(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithP1First(false))
+	for _, p := range []Point{{5, 4}, {5, 5}, {4, 5}} {
+		if _, err := g.Mark(p, *g.CurrentPlayer()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := g.Transcript()
+	if want := "f5f6e6"; s != want {
+		t.Errorf("Transcript(), want: %v, got %v", want, s)
+	}
+	h, err := ParseTranscript(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := 0; y < Height; y++ {
+		for x := 0; x < Width; x++ {
+			p := Point{x, y}
+			if want, got := g.Cell(p), h.Cell(mirror(p)); want != 0 && got != 3-want {
+				t.Errorf("Cell(%v), want: %v, got %v", mirror(p), 3-want, got)
+			}
+		}
+	}
+}
+
+func TestParseTranscriptInvalid(t *testing.T) {
+	tests := map[string]string{
+		"odd length":   "f5d",
+		"off board":    "f5z9",
+		"illegal move": "f5a1",
+	}
+	for name, s := range tests {
+		if _, err := ParseTranscript(s); err == nil {
+			t.Errorf("%v: ParseTranscript(%v), want error, got nil", name, s)
+		}
+	}
+}
+
+func TestGGF(t *testing.T) {
+	var g *GameBoard
+	playRandomGame(3, func(h *GameBoard) { g = h })
+	g.P1().name, g.P2().name = "Alice", "Bob]"
+	info := GameInfo{
+		Place:       "club",
+		Date:        time.Date(2024, 5, 1, 18, 30, 0, 0, time.UTC),
+		TimeControl: "05:00//02:00",
+	}
+
+	s := g.GGF(info)
+	for _, want := range []string{"(;GM[Othello]", "PB[Alice]", `PW[Bob\]]`, "TI[05:00//02:00]", "DT[2024.05.01_18:30:00.UTC]", ";)"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("GGF(), want %v in %v", want, s)
+		}
+	}
+
+	h, gotInfo, err := ParseGGF(s)
+	if err != nil {
+		t.Fatalf("ParseGGF(%v), got error %v", s, err)
+	}
+	if gotInfo != info {
+		t.Errorf("ParseGGF() info, want: %v, got %v", info, gotInfo)
+	}
+	if h.P1().Name() != "Alice" || h.P2().Name() != "Bob]" {
+		t.Errorf("ParseGGF() names, want: Alice Bob], got %v %v", h.P1().Name(), h.P2().Name())
+	}
+	if h.Hash() != g.Hash() || len(h.History()) != len(g.History()) {
+		t.Errorf("ParseGGF() does not rebuild the game")
+	}
+	if got, want := h.GGF(info), s; got != want {
+		t.Errorf("GGF() after ParseGGF(), want: %v, got %v", want, got)
+	}
+}
+
+func TestGGFFewestDiscs(t *testing.T) {
+	var g *GameBoard
+	playRandomGameWith(4, func(h *GameBoard) { g = h }, WithObjective(FewestDiscs))
+	s := g.GGF(GameInfo{})
+	if !strings.Contains(s, "TY[8a]") {
+		t.Errorf("GGF(), want TY[8a] in %v", s)
+	}
+	h, _, err := ParseGGF(s)
+	if err != nil {
+		t.Fatalf("ParseGGF(%v), got error %v", s, err)
+	}
+	if h.Objective() != FewestDiscs || h.Hash() != g.Hash() {
+		t.Errorf("ParseGGF() does not rebuild the FewestDiscs game")
+	}
+	if got, want := h.GGF(GameInfo{}), s; got != want {
+		t.Errorf("GGF() after ParseGGF(), want: %v, got %v", want, got)
+	}
+}
+
+func TestGGFHandicap(t *testing.T) {
+	var g *GameBoard
+	playRandomGameWith(5, func(h *GameBoard) { g = h }, WithHandicap(2, 2))
+	s := g.GGF(GameInfo{})
+	if !strings.Contains(s, "HA[W2]") {
+		t.Errorf("GGF(), want HA[W2] in %v", s)
+	}
+	h, _, err := ParseGGF(s)
+	if err != nil {
+		t.Fatalf("ParseGGF(%v), got error %v", s, err)
+	}
+	if h.Handicap() != g.Handicap() || h.Hash() != g.Hash() {
+		t.Errorf("ParseGGF() does not rebuild the handicap game, got %v", h.Handicap())
+	}
+
+	h, _, err = ParseGGF("(;GM[Othello]HA[W1]B[f5];)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.Cell(Point{0, 0}), 2; got != want {
+		t.Errorf("ParseGGF() with HA[W1] and no BO: Cell(a1), want: %v, got %v", want, got)
+	}
+}
+
+func TestParseGGF(t *testing.T) {
+	s := `(;GM[Othello]PC[GGS/os]DT[1079283829]PB[black]PW[white]RE[-64.000:r]TI[5:00//02:00]
+TY[8]BO[8 ---------------------------O*------*O--------------------------- *]
+B[f5//1.2]W[d6/-0.5/3.1]B[c3];)`
+	g, info, err := ParseGGF(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.Transcript(), "f5d6c3"; got != want {
+		t.Errorf("Transcript(), want: %v, got %v", want, got)
+	}
+	if got, want := info.Date, time.Unix(1079283829, 0).UTC(); !got.Equal(want) {
+		t.Errorf("Date, want: %v, got %v", want, got)
+	}
+	if !g.P1().Surrendered() || g.Result() != g.P2() {
+		t.Errorf("Result(), want white to win by resignation")
+	}
+
+	tests := map[string]string{
+		"not a record":   "GM[Othello]",
+		"other game":     "(;GM[Go];)",
+		"wrong colour":   "(;GM[Othello]W[f5];)",
+		"illegal move":   "(;GM[Othello]B[a1];)",
+		"board size":     "(;GM[Othello]TY[9];)",
+		"handicap":       "(;GM[Othello]HA[B5];)",
+		"start position": "(;GM[Othello]BO[8 --------------------------------------------------------------x- *];)",
+		"unterminated":   "(;GM[Othello;)",
+	}
+	for name, s := range tests {
+		if _, _, err := ParseGGF(s); err == nil {
+			t.Errorf("%v: ParseGGF(%v), want error, got nil", name, s)
+		}
+	}
+}
+
+func TestGGFFromPosition(t *testing.T) {
+	g := mustParsePosition(t, "--------------------X------XXX-----OXO-----O-------------------- O")
+	g.P1().name, g.P2().name = "x", "o"
+	p := g.CurrentPlayer().PossibleMoves()[0]
+	g.Mark(p, *g.CurrentPlayer())
+
+	h, _, err := ParseGGF(g.GGF(GameInfo{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.P1().Name(), "o"; got != want {
+		t.Errorf("name of the first player, want: %v, got %v", want, got)
+	}
+	// The first player of the record becomes player 1, so the colours swap.
+	for y := 0; y < Height; y++ {
+		for x := 0; x < Width; x++ {
+			q := Point{x, y}
+			if want, got := g.Cell(q), h.Cell(mirror(q)); want != 0 && got != 3-want {
+				t.Fatalf("Cell(%v), want: %v, got %v", mirror(q), 3-want, got)
+			}
+		}
+	}
+}
+
+func TestGGFWhiteToMove(t *testing.T) {
+	bo := "BO[8 ---------------------------O*------*O--------------------------- O]"
+	g, _, err := ParseGGF("(;GM[Othello]PB[b]PW[w]TY[8]" + bo + "W[d6]B[c4];)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := g.GGF(GameInfo{})
+	for _, want := range []string{"PB[b]PW[w]", bo, "W[d6]B[c4]"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("GGF(), want %v in %v", want, s)
+		}
+	}
+	h, _, err := ParseGGF(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again := h.GGF(GameInfo{}); again != s {
+		t.Errorf("GGF() of the parsed record, want: %v, got %v", s, again)
+	}
+}
@@ -0,0 +1,597 @@
+// Package reversi implements the rules of Reversi (Othello): the board, the
+// players, legal move generation and disc flipping. It has no knowledge of how
+// a game is presented, so it can be shared by the game server, the console
+// game, bots and analysis tools.
+package reversi
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Width and Height are the size of the standard board. Position and the
+	// engines built on it only play on this board.
+	Width  = 8
+	Height = 8
+
+	// MinSize and MaxSize bound the board sizes accepted by WithSize.
+	MinSize = 4
+	MaxSize = 26
+)
+
+// Point is a cell on the board. X is the column and Y is the row, both
+// counted from 0.
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// ToNotation returns the notation of p on the standard board.
+func (p Point) ToNotation() (Notation, error) {
+	return p.ToNotationOn(Width)
+}
+
+// ToNotationOn returns the notation of p on a board of the given size. Columns
+// run from a and rows from 1, so the last cell of a 10x10 board is "j10".
+func (p Point) ToNotationOn(size int) (Notation, error) {
+	if p.X < 0 || p.X >= size || p.Y < 0 || p.Y >= size || size > MaxSize {
+		return Notation(""), errors.New("invalid input")
+	}
+	return Notation(fmt.Sprintf("%c%d", 'a'+p.X, 1+p.Y)), nil
+}
+
+// Notation is the human readable form of a Point, e.g. "c4".
+type Notation string
+
+// ToPoint returns the cell of n on the standard board.
+func (n Notation) ToPoint() (Point, error) {
+	return n.ToPointOn(Width)
+}
+
+// ToPointOn returns the cell of n on a board of the given size.
+func (n Notation) ToPointOn(size int) (Point, error) {
+	if len(n) < 2 || n[0] < 'a' || n[0] > 'z' || n[1] < '1' || n[1] > '9' {
+		return Point{}, errors.New("invalid input")
+	}
+	x := int(n[0] - byte('a'))
+	row, err := strconv.Atoi(string(n[1:]))
+	if err != nil || x >= size || row > size {
+		return Point{}, errors.New("invalid input")
+	}
+	return Point{x, row - 1}, nil
+}
+
+// Objective is what the players of a game aim for.
+type Objective int
+
+const (
+	// MostDiscs is the standard rule: the player with more discs wins.
+	MostDiscs Objective = iota
+	// FewestDiscs is anti-reversi: the player with fewer discs wins.
+	FewestDiscs
+)
+
+// sign is 1 when discs count for the player owning them and -1 when they
+// count against.
+func (o Objective) sign() int {
+	if o == FewestDiscs {
+		return -1
+	}
+	return 1
+}
+
+type GameCfg struct {
+	p1First   bool
+	showHint  bool
+	size      int
+	layout    [][]int
+	blocked   []Point
+	objective Objective
+	handicap  Handicap
+	// more holds the players after p1 and p2.
+	more []Player
+}
+
+type GameCfgFunc func(*GameCfg)
+
+func defaultGameConfig() GameCfg {
+	return GameCfg{
+		p1First:  true,
+		showHint: true,
+		size:     Width,
+	}
+}
+
+func WithP1First(p1First bool) GameCfgFunc {
+	return func(cfg *GameCfg) {
+		cfg.p1First = p1First
+	}
+}
+
+func WithShowHint(showHint bool) GameCfgFunc {
+	return func(cfg *GameCfg) {
+		cfg.showHint = showHint
+	}
+}
+
+// WithSize sets the number of rows and columns of the board. Sizes must be
+// even and within MinSize and MaxSize; others fall back to the standard
+// board.
+func WithSize(size int) GameCfgFunc {
+	return func(cfg *GameCfg) {
+		cfg.size = size
+	}
+}
+
+// WithObjective sets how the winner is decided, MostDiscs by default.
+func WithObjective(objective Objective) GameCfgFunc {
+	return func(cfg *GameCfg) {
+		cfg.objective = objective
+	}
+}
+
+// validSize reports whether a board of size rows and columns can be played.
+func validSize(size int) bool {
+	return size >= MinSize && size <= MaxSize && size%2 == 0
+}
+
+// newGameConfig applies cfgFuncs to the default configuration.
+func newGameConfig(cfgFuncs ...GameCfgFunc) GameCfg {
+	cfg := defaultGameConfig()
+	for _, cfgFunc := range cfgFuncs {
+		cfgFunc(&cfg)
+	}
+	if cfg.layout != nil && !validLayout(cfg.layout) {
+		cfg.layout = nil
+	}
+	if cfg.layout != nil {
+		cfg.size = len(cfg.layout)
+	}
+	if !validSize(cfg.size) {
+		cfg.size = Width
+	}
+	if !cfg.handicap.valid() {
+		cfg.handicap = Handicap{}
+	}
+	if len(cfg.more) > MaxPlayers-2 {
+		cfg.more = cfg.more[:MaxPlayers-2]
+	}
+	return cfg
+}
+
+// GameBoard holds the state of one game: the cells, the turn counter and the
+// players. board is indexed board[y][x] and holds 0 for an empty cell or the
+// token owning the disc. On the standard board the discs are also kept in a
+// bitboard, which moves are generated from.
+type GameBoard struct {
+	cfg   GameCfg
+	bb    bitboard
+	hash  uint64
+	board [][]int
+	turn  int
+	// toMove is the token of the player to move.
+	toMove int
+	// walls is set when some cells are Blocked.
+	walls bool
+	// players holds the player of each token at index token-1.
+	players []*Player
+
+	// start is the position string the game was set up from.
+	start string
+	// history holds the moves played, redo the moves taken back by Undo.
+	history []Move
+	redo    []Move
+}
+
+// NewGameBoard sets up the starting position for p1 and p2: four discs in the
+// centre of the board, token 1 on the diagonal from the top left, unless
+// WithLayout gives another one. With WithMorePlayers every player starts with
+// one disc in the centre instead. The players are copied, so use P1, P2 and
+// Players to follow their state during the game.
+func NewGameBoard(p1, p2 Player, cfgFuncs ...GameCfgFunc) *GameBoard {
+	cfg := newGameConfig(cfgFuncs...)
+	board := emptyBoard(cfg.size)
+	if cfg.layout != nil {
+		for y, row := range cfg.layout {
+			copy(board[y], row)
+		}
+	} else if len(cfg.more) > 0 {
+		multiStart(board, 2+len(cfg.more))
+	} else {
+		c := cfg.size / 2
+		board[c-1][c-1], board[c-1][c] = 1, 2
+		board[c][c-1], board[c][c] = 2, 1
+	}
+	for _, p := range cfg.blocked {
+		if p.X >= 0 && p.X < cfg.size && p.Y >= 0 && p.Y < cfg.size {
+			board[p.Y][p.X] = Blocked
+		}
+	}
+	for _, p := range handicapCorners(cfg.size)[:cfg.handicap.Corners] {
+		if board[p.Y][p.X] == 0 {
+			board[p.Y][p.X] = cfg.handicap.Token
+		}
+	}
+
+	toMove := 1
+	if !cfg.p1First {
+		toMove = 2
+	}
+	return newGame(board, toMove, p1, p2, cfg)
+}
+
+// newGame sets up a game of p1, p2 and the players of cfg on board with the
+// player holding toMove to move.
+func newGame(board [][]int, toMove int, p1, p2 Player, cfg GameCfg) *GameBoard {
+	g := GameBoard{
+		cfg:     cfg,
+		board:   board,
+		turn:    1,
+		toMove:  toMove,
+		players: []*Player{&p1, &p2},
+	}
+	for _, p := range cfg.more {
+		g.players = append(g.players, &p)
+	}
+	for _, row := range board {
+		for _, cell := range row {
+			g.walls = g.walls || cell == Blocked
+		}
+	}
+	if g.standard() {
+		g.bb = newBitboard(board)
+	}
+	for y, row := range board {
+		for x, cell := range row {
+			if cell > 0 {
+				g.hash ^= g.cellKey(cell, Point{x, y})
+			}
+		}
+	}
+	g.hash ^= zobristTurn[g.toMove-1]
+	g.refreshPlayers()
+	g.start = g.String()
+	return &g
+}
+
+func emptyBoard(size int) [][]int {
+	board := make([][]int, size)
+	for y := range board {
+		board[y] = make([]int, size)
+	}
+	return board
+}
+
+// standard reports whether g is a game of two players on the standard board
+// without blocked cells, where the bitboard and the engines are available.
+func (g GameBoard) standard() bool {
+	return g.cfg.size == Width && !g.walls && len(g.players) == 2
+}
+
+// fromStart reports whether g was set up from the four discs in the centre,
+// without a layout, handicap or position string moving them.
+func (g GameBoard) fromStart() bool {
+	s := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithSize(g.cfg.size)).String()
+	cells := len(s) - 2
+	return g.start[:cells] == s[:cells]
+}
+
+// Objective returns how the winner of g is decided.
+func (g GameBoard) Objective() Objective {
+	return g.cfg.objective
+}
+
+// Handicap returns the handicap the game started with.
+func (g GameBoard) Handicap() Handicap {
+	return g.cfg.handicap
+}
+
+// Size returns the number of rows and columns of the board.
+func (g GameBoard) Size() int {
+	return g.cfg.size
+}
+
+// P1 returns the player holding token 1.
+func (g GameBoard) P1() *Player {
+	return g.players[0]
+}
+
+// P2 returns the player holding token 2.
+func (g GameBoard) P2() *Player {
+	return g.players[1]
+}
+
+// Players returns every player of the game in the order of their tokens.
+func (g GameBoard) Players() []*Player {
+	return append([]*Player(nil), g.players...)
+}
+
+// Turn returns the turn counter, starting from 1.
+func (g GameBoard) Turn() int {
+	return g.turn
+}
+
+// Cell returns the token at p, Blocked for a blocked cell, or 0 if the cell
+// is empty or p is off the board.
+func (g GameBoard) Cell(p Point) int {
+	if p.X < 0 || p.X >= g.cfg.size || p.Y < 0 || p.Y >= g.cfg.size {
+		return 0
+	}
+	return g.board[p.Y][p.X]
+}
+
+// Board returns a copy of the cells, indexed [y][x].
+func (g GameBoard) Board() [][]int {
+	res := make([][]int, len(g.board))
+	for i, row := range g.board {
+		res[i] = append([]int(nil), row...)
+	}
+	return res
+}
+
+// Clone returns a copy of g, players included, that can be played on or
+// searched without touching g.
+func (g GameBoard) Clone() *GameBoard {
+	c := g
+	c.board = g.Board()
+	c.players = make([]*Player, len(g.players))
+	for i, p := range g.players {
+		cp := *p
+		c.players[i] = &cp
+	}
+	c.history = append([]Move(nil), g.history...)
+	c.redo = append([]Move(nil), g.redo...)
+	return &c
+}
+
+func (g GameBoard) Print() {
+	currPlayer := g.CurrentPlayer()
+
+	n := g.cfg.size
+	rowWidth := len(strconv.Itoa(n))
+	line := "+" + strings.Repeat("-", rowWidth) + strings.Repeat("+-", n) + "+"
+
+	hintMark := " "
+	if g.cfg.showHint {
+		hintMark = "?"
+	}
+
+	deduceMark := func(i, j int) string {
+		switch g.board[j][i] {
+		case 1:
+			return "●"
+		case 2:
+			return "○"
+		case 3:
+			return "▲"
+		case 4:
+			return "■"
+		case Blocked:
+			return "#"
+		}
+		point := Point{j, i}
+		_, ok := currPlayer.possibleMoves[point]
+		if !ok {
+			return " "
+		}
+		return hintMark
+	}
+
+	fmt.Println(line)
+	fmt.Printf("|%s|", strings.Repeat(" ", rowWidth))
+	for i := 0; i < n; i++ {
+		fmt.Printf("%c|", 'a'+i)
+	}
+	fmt.Println()
+	fmt.Println(line)
+	for i, row := range g.board {
+		fmt.Printf("|%*d|", rowWidth, i+1)
+		for j := range row {
+			fmt.Printf("%s|", deduceMark(j, i))
+		}
+		fmt.Printf("\n%s\n", line)
+	}
+	fmt.Printf("Turn %2d", g.turn)
+	for _, p := range g.players {
+		fmt.Printf(" | %s: %2d", p.name, p.score)
+	}
+	fmt.Print("\n\n")
+}
+
+// PossibleMoves returns every legal move of the player with the given token,
+// mapped to the discs the move would flip.
+func (g GameBoard) PossibleMoves(player int) map[Point][]Point {
+	if !g.standard() {
+		return g.scanPossibleMoves(player)
+	}
+	own, opp := g.bb.own(player), g.bb.oppo(player)
+	moves := legalMoves(own, opp)
+	pMoves := make(map[Point][]Point, bits.OnesCount64(moves))
+	for ; moves != 0; moves &= moves - 1 {
+		sq := bits.TrailingZeros64(moves)
+		pMoves[squarePoint(sq)] = maskPoints(flips(own, opp, sq))
+	}
+	return pMoves
+}
+
+// scanPossibleMoves is PossibleMoves walking the board cell by cell. It is
+// used on boards other than the standard one and kept as the reference the
+// bitboard generator is checked against. Any disc that is neither empty,
+// blocked nor of player belongs to an opponent.
+func (g GameBoard) scanPossibleMoves(player int) map[Point][]Point {
+	pMoves := make(map[Point][]Point)
+	n := len(g.board)
+
+	oppo := func(cell int) bool {
+		return cell > 0 && cell != player
+	}
+
+	// Directions - up/down, left/right, diagonals
+	dirs := [8][2]int{
+		{-1, 0}, {1, 0}, {0, -1}, {0, 1},
+		{-1, -1}, {-1, 1}, {1, -1}, {1, 1},
+	}
+
+	for y, row := range g.board {
+		for x, cell := range row {
+			if !oppo(cell) {
+				continue
+			}
+
+			for _, dir := range dirs {
+				dy, dx := dir[0], dir[1]
+				ty, tx := y+dy, x+dx
+
+				if tx == -1 || tx == n || ty == -1 || ty == n || g.board[ty][tx] != 0 {
+					continue
+				}
+
+				flips := []Point{}
+				for nx, ny := x, y; nx >= 0 && nx < n && ny >= 0 && ny < n; nx, ny = nx-dx, ny-dy {
+					if g.board[ny][nx] != player && !oppo(g.board[ny][nx]) {
+						break
+					}
+					if g.board[ny][nx] == player {
+						p := Point{tx, ty}
+						_, ok := pMoves[p]
+						if !ok {
+							pMoves[p] = flips
+						} else {
+							pMoves[p] = append(pMoves[p], flips...)
+						}
+						break
+					}
+					flips = append(flips, Point{nx, ny})
+				}
+			}
+		}
+	}
+	return pMoves
+}
+
+// EndGame reports whether no player can move.
+func (g GameBoard) EndGame() bool {
+	for _, p := range g.players {
+		if len(p.possibleMoves) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Mark places a disc of player at point, flips the captured discs and hands
+// the turn to the next player. It returns the number of discs changed, including
+// the placed one.
+func (g *GameBoard) Mark(point Point, player Player) (int, error) {
+	if player.token != g.toMove {
+		return 0, errors.New("not your turn")
+	}
+	f, ok := g.CurrentPlayer().possibleMoves[point]
+	if !ok {
+		return 0, errors.New("invalid move")
+	}
+	m := Move{Point: point, Token: player.token, Flips: append([]Point(nil), f...), Turn: g.turn}
+	if len(g.players) > 2 {
+		m.Owners = g.owners(m.Flips)
+	}
+	g.replay(m)
+	g.record(m)
+	g.advance()
+	return len(m.Flips) + 1, nil
+}
+
+// Pass hands the turn to the next player. It is only allowed when the player to
+// move has no possible moves and the game is not over.
+func (g *GameBoard) Pass() error {
+	if g.EndGame() {
+		return errors.New("game is over")
+	}
+	if len(g.CurrentPlayer().possibleMoves) > 0 {
+		return errors.New("cannot pass with possible moves")
+	}
+	g.record(Move{Token: g.toMove, Pass: true, Turn: g.turn})
+	g.advance()
+	return nil
+}
+
+// MustPass reports whether the player to move has no possible moves while
+// the game goes on.
+func (g GameBoard) MustPass() bool {
+	return !g.EndGame() && len(g.CurrentPlayer().possibleMoves) == 0
+}
+
+// advance ends the turn of the player to move.
+func (g *GameBoard) advance() {
+	g.setToMove(g.next(g.toMove))
+	g.turn++
+	g.refreshPlayers()
+}
+
+// RefreshState recomputes possible moves and scores of the players. Mark,
+// Pass, Undo and Redo already do so.
+func (g *GameBoard) RefreshState() {
+	g.refreshPlayers()
+}
+
+func (g *GameBoard) refreshPlayers() {
+	for i, p := range g.players {
+		p.possibleMoves, p.score = g.PossibleMoves(i+1), g.count(i+1)
+	}
+}
+
+// count returns the number of discs of token.
+func (g GameBoard) count(token int) int {
+	if g.standard() {
+		return g.bb.count(token)
+	}
+	n := 0
+	for _, row := range g.board {
+		for _, cell := range row {
+			if cell == token {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// CurrentPlayer returns the player to move.
+func (g GameBoard) CurrentPlayer() *Player {
+	return g.players[g.toMove-1]
+}
+
+// Result returns the winner, or nil for a draw. The winner has the most
+// discs, or the fewest under FewestDiscs. A player who surrendered always
+// loses.
+func (g GameBoard) Result() *Player {
+	var winner *Player
+	draw := false
+	for _, p := range g.players {
+		if p.surrender {
+			continue
+		}
+		if winner == nil {
+			winner = p
+			continue
+		}
+		diff := g.cfg.objective.sign() * (p.score - winner.score)
+		if diff > 0 {
+			winner, draw = p, false
+		} else if diff == 0 {
+			draw = true
+		}
+	}
+	if draw {
+		return nil
+	}
+	return winner
+}
+
+// Surrender marks p as having given up the game.
+func (g GameBoard) Surrender(p *Player) {
+	p.surrender = true
+}
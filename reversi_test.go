@@ -0,0 +1,393 @@
+package reversi
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPossibleMoves(t *testing.T) {
+	g := mustParsePosition(t, `
+		--------
+		--------
+		--------
+		---XO---
+		---OX---
+		--------
+		--------
+		--------
+		X`)
+
+	if got, want := g.PossibleMoves(1), map[Point][]Point{
+		{4, 2}: {{4, 3}},
+		{5, 3}: {{4, 3}},
+		{2, 4}: {{3, 4}},
+		{3, 5}: {{3, 4}},
+	}; !equalMapUnorderedSlice(got, want) {
+		t.Errorf("PossibleMoves(%v, %v), want: %v, got %v", "board", 1, want, got)
+	}
+}
+
+func TestPossibleMovesForDiagonals(t *testing.T) {
+	g := mustParsePosition(t, `
+		--------
+		--------
+		----O---
+		---XOX--
+		---OO---
+		--------
+		--------
+		--------
+		X`)
+
+	if got, want := g.PossibleMoves(1), map[Point][]Point{
+		{3, 1}: {{4, 2}},
+		{5, 1}: {{4, 2}},
+		{3, 5}: {{3, 4}, {4, 4}},
+		{5, 5}: {{4, 4}},
+	}; !equalMapUnorderedSlice(got, want) {
+		t.Errorf("PossibleMoves(%v, %v), want: %v, got %v", "board", 1, want, got)
+	}
+}
+
+func TestPossibleMovesForAllDirections(t *testing.T) {
+	g := mustParsePosition(t, `
+		-OOOOOOO
+		-OXXXXXO
+		-OXXXXXO
+		-OXX-XXO
+		-OXXXXXO
+		-OXXXXXO
+		-OOOOOOO
+		--------
+		X`)
+
+	if got, want := g.PossibleMoves(2), map[Point][]Point{
+		{4, 3}: {
+			{2, 1}, {4, 1}, {6, 1},
+			{3, 2}, {4, 2}, {5, 2},
+			{2, 3}, {3, 3}, {5, 3}, {6, 3},
+			{3, 4}, {4, 4}, {5, 4},
+			{2, 5}, {4, 5}, {6, 5},
+		},
+	}; !equalMapUnorderedSlice(got, want) {
+		t.Errorf("PossibleMoves(%v, %v), want: %v, got %v", "board", 1, want, got)
+	}
+}
+
+// mustParsePosition sets up a game of two default players from a position
+// string.
+func mustParsePosition(t *testing.T, s string) *GameBoard {
+	t.Helper()
+	g, err := ParsePosition(s, *NewPlayer(1), *NewPlayer(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestParsePosition(t *testing.T) {
+	start := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	if got, want := start.String(), "---------------------------XO------OX--------------------------- X"; got != want {
+		t.Errorf("String(), want: %v, got %v", want, got)
+	}
+
+	for seed := int64(0); seed < 5; seed++ {
+		playRandomGame(seed, func(g *GameBoard) {
+			h, err := ParsePosition(g.String(), *NewPlayer(1), *NewPlayer(2))
+			if err != nil {
+				t.Fatalf("ParsePosition(%v), got error %v", g, err)
+			}
+			if h.String() != g.String() || h.Hash() != g.Hash() || h.CurrentPlayer().Token() != g.CurrentPlayer().Token() {
+				t.Fatalf("ParsePosition(%v), got %v", g, h)
+			}
+		})
+	}
+
+	g := mustParsePosition(t, `
+		--------
+		--------
+		--------
+		---xo---
+		---ox---
+		--------
+		--------
+		--------
+		o`)
+	if got, want := g.CurrentPlayer().Token(), 2; got != want {
+		t.Errorf("CurrentPlayer(), want: %v, got %v", want, got)
+	}
+
+	tests := map[string]string{
+		"empty":        "",
+		"no side":      "---------------------------XO------OX---------------------------",
+		"short":        "---------------------------XO------OX-------------------------- X",
+		"long":         "---------------------------XO------OX---------------------------- X",
+		"invalid cell": "---------------------------XO------OX------------------------1-- X",
+		"invalid side": "---------------------------XO------OX--------------------------- -",
+	}
+	for name, s := range tests {
+		if _, err := ParsePosition(s, *NewPlayer(1), *NewPlayer(2)); err == nil {
+			t.Errorf("%v: ParsePosition(%q), want error, got nil", name, s)
+		}
+	}
+}
+
+func equalMapUnorderedSlice(got, want map[Point][]Point) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for k, gotV := range got {
+		wantV, ok := want[k]
+		if !ok {
+			return false
+		}
+		sort.Slice(gotV, func(i, j int) bool {
+			if gotV[i].X == gotV[j].X {
+				return gotV[i].Y < gotV[j].Y
+			}
+			return gotV[i].X < gotV[j].X
+		})
+		sort.Slice(wantV, func(i, j int) bool {
+			if wantV[i].X == wantV[j].X {
+				return wantV[i].Y < wantV[j].Y
+			}
+			return wantV[i].X < wantV[j].X
+		})
+		if !reflect.DeepEqual(gotV, wantV) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPointToNotation(t *testing.T) {
+	tests := map[string]struct {
+		notation Notation
+		point    Point
+		err      error
+	}{
+		"valid input": {
+			point:    Point{0, 4},
+			notation: "a5",
+			err:      nil,
+		},
+		"another valid input": {
+			point:    Point{6, 1},
+			notation: "g2",
+			err:      nil,
+		},
+		"out of y-range": {
+			point:    Point{5, 10},
+			notation: "",
+			err:      errors.New("invalid input"),
+		},
+		"out of x-range": {
+			point:    Point{4, 12},
+			notation: "",
+			err:      errors.New("invalid input"),
+		},
+		"irrelavant input": {
+			point:    Point{-1, -1},
+			notation: "",
+			err:      errors.New("invalid input"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, gotError := test.point.ToNotation()
+			want, wantError := test.notation, test.err
+			if got != want || (gotError != nil && wantError == nil) {
+				t.Errorf("%v.ToNotation(), want: (%v, %v), got (%v, %v)", test.point, want, wantError, got, gotError)
+			}
+		})
+	}
+}
+
+func TestNotationToPoint(t *testing.T) {
+	tests := map[string]struct {
+		notation Notation
+		point    Point
+		err      error
+	}{
+		"valid input": {
+			notation: "a5",
+			point:    Point{0, 4},
+			err:      nil,
+		},
+		"another valid input": {
+			notation: "g2",
+			point:    Point{6, 1},
+			err:      nil,
+		},
+		"out of y-range": {
+			notation: "a9",
+			point:    Point{},
+			err:      errors.New("invalid input"),
+		},
+		"out of x-range": {
+			notation: "x6",
+			point:    Point{},
+			err:      errors.New("invalid input"),
+		},
+		"irrelavant input": {
+			notation: "asdlfkjasldf",
+			point:    Point{},
+			err:      errors.New("invalid input"),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, gotError := test.notation.ToPoint()
+			want, wantError := test.point, test.err
+			if got != want || (gotError != nil && wantError == nil) {
+				t.Errorf("%v.ToPoint(), want: (%v, %v), got (%v, %v)", test.notation, want, wantError, got, gotError)
+			}
+		})
+	}
+}
+
+func TestNotationOn(t *testing.T) {
+	tests := map[string]struct {
+		size     int
+		notation Notation
+		point    Point
+		valid    bool
+	}{
+		"6x6":             {6, "f6", Point{5, 5}, true},
+		"two-digit row":   {10, "j10", Point{9, 9}, true},
+		"16x16":           {16, "p16", Point{15, 15}, true},
+		"column too far":  {16, "q1", Point{16, 0}, false},
+		"row too far":     {10, "a11", Point{0, 10}, false},
+		"beyond 8 on 8x8": {8, "i9", Point{8, 8}, false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			gotPoint, err := test.notation.ToPointOn(test.size)
+			if (err == nil) != test.valid || test.valid && gotPoint != test.point {
+				t.Errorf("%v.ToPointOn(%v), want: (%v, valid %v), got (%v, %v)", test.notation, test.size, test.point, test.valid, gotPoint, err)
+			}
+			gotNotation, err := test.point.ToNotationOn(test.size)
+			if (err == nil) != test.valid || test.valid && gotNotation != test.notation {
+				t.Errorf("%v.ToNotationOn(%v), want: (%v, valid %v), got (%v, %v)", test.point, test.size, test.notation, test.valid, gotNotation, err)
+			}
+		})
+	}
+}
+
+func TestBoardSizes(t *testing.T) {
+	for _, size := range []int{6, 10, 12, 16} {
+		g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithSize(size))
+		if got := len(g.Board()); got != size {
+			t.Fatalf("WithSize(%v): rows, want: %v, got %v", size, size, got)
+		}
+		if got, want := len(g.P1().PossibleMoves()), 4; got != want {
+			t.Errorf("WithSize(%v): possible moves at the start, want: %v, got %v", size, want, got)
+		}
+		c := size / 2
+		if g.Cell(Point{c - 1, c - 1}) != 1 || g.Cell(Point{c, c - 1}) != 2 {
+			t.Errorf("WithSize(%v): starting discs not in the centre", size)
+		}
+
+		for seed := int64(0); seed < 3; seed++ {
+			var last *GameBoard
+			playRandomGameWith(seed, func(h *GameBoard) {
+				if h.P1().Score()+h.P2().Score() != 4+len(h.History())-passes(h) {
+					t.Fatalf("size %v seed %v: scores out of sync", size, seed)
+				}
+				last = h
+			}, WithSize(size))
+
+			h, err := ParsePosition(last.String(), *NewPlayer(1), *NewPlayer(2))
+			if err != nil || h.Size() != size || h.Hash() != last.Hash() {
+				t.Errorf("size %v seed %v: ParsePosition(String()) = %v, %v", size, seed, h, err)
+			}
+			h, err = ParseTranscript(last.Transcript(), WithSize(size))
+			if err != nil || h.Hash() != last.Hash() {
+				t.Errorf("size %v seed %v: ParseTranscript(Transcript()), got error %v", size, seed, err)
+			}
+			if err := last.Undo(); err != nil || last.Hash() == h.Hash() {
+				t.Errorf("size %v seed %v: Undo() does not change the hash", size, seed)
+			}
+		}
+	}
+
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithSize(7))
+	if got, want := g.Size(), Width; got != want {
+		t.Errorf("WithSize(7): Size(), want: %v, got %v", want, got)
+	}
+}
+
+func TestComputerOnOtherSizes(t *testing.T) {
+	c := NewPlayer(1, WithPlayerType(Computer))
+	g := NewGameBoard(*c, *NewPlayer(2), WithSize(10))
+	p, err := g.P1().ChooseMove(g)
+	if err != nil || !g.P1().CanMove(p) {
+		t.Errorf("ChooseMove() on 10x10, want a legal move, got (%v, %v)", p, err)
+	}
+	if _, err := g.Solve(Exact); err == nil {
+		t.Errorf("Solve() on 10x10, want error, got nil")
+	}
+}
+
+func passes(g *GameBoard) int {
+	n := 0
+	for _, m := range g.History() {
+		if m.Pass {
+			n++
+		}
+	}
+	return n
+}
+
+func TestResultFewestDiscs(t *testing.T) {
+	position := `
+		XXXXXXXX
+		XXXXXXXX
+		XXXXXXXX
+		XXXXXXXX
+		OOOOOOOO
+		OOOOOOOO
+		OOOOOOOO
+		OOOOOOO-
+		X`
+	tests := map[string]struct {
+		objective Objective
+		want      int
+	}{
+		"most discs":   {MostDiscs, 1},
+		"fewest discs": {FewestDiscs, 2},
+	}
+	for name, test := range tests {
+		g, err := ParsePosition(position, *NewPlayer(1), *NewPlayer(2), WithObjective(test.objective))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := g.Result(); got == nil || got.Token() != test.want {
+			t.Errorf("%v: Result(), want: player %v, got %v", name, test.want, got)
+		}
+		g.Surrender(g.P2())
+		if got := g.Result(); got == nil || got.Token() != 1 {
+			t.Errorf("%v: Result() after P2 surrendered, want: player 1, got %v", name, got)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	before := g.String()
+	c := g.Clone()
+	if _, err := c.Mark(c.P1().PossibleMoves()[0], *c.P1()); err != nil {
+		t.Fatal(err)
+	}
+	c.Surrender(c.P2())
+	if g.String() != before || g.Turn() != 1 || g.CanUndo() || g.P1().Score() != 2 || g.P2().Surrendered() {
+		t.Errorf("Clone() then playing on the copy, want the game unchanged, got %v", g)
+	}
+	if c.String() == before || !c.CanUndo() {
+		t.Errorf("Clone() then playing on the copy, want the copy played on, got %v", c)
+	}
+}
@@ -0,0 +1,312 @@
+package reversi
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+	"time"
+)
+
+const (
+	// winScore is the score of a finished game won by the side to move,
+	// before adding the disc difference. It is above any heuristic score.
+	winScore = 100000
+	infinity = winScore * 2
+
+	// checkInterval is how many nodes are searched between deadline checks.
+	checkInterval = 1024
+
+	// defaultTableSize is the number of slots of the transposition table a
+	// Searcher or Solve creates when none is given.
+	defaultTableSize = 1 << 18
+
+	// fewestDiscsSalt keeps the scores of FewestDiscs searches apart from the
+	// MostDiscs ones when both share a table.
+	fewestDiscsSalt uint64 = 0x3c6ef372fe94f82b
+)
+
+// squareOrder ranks the cells for move ordering: corners first, then the
+// edges and the centre, and the squares next to the corners last.
+var squareOrder = [Width * Height]int{
+	9, 1, 7, 6, 6, 7, 1, 9,
+	1, 0, 3, 3, 3, 3, 0, 1,
+	7, 3, 5, 4, 4, 5, 3, 7,
+	6, 3, 4, 4, 4, 4, 3, 6,
+	6, 3, 4, 4, 4, 4, 3, 6,
+	7, 3, 5, 4, 4, 5, 3, 7,
+	1, 0, 3, 3, 3, 3, 0, 1,
+	9, 1, 7, 6, 6, 7, 1, 9,
+}
+
+type SearchCfg struct {
+	depth     int
+	timeLimit time.Duration
+	eval      Evaluator
+	tt        *TranspositionTable
+	objective Objective
+}
+
+type SearchCfgFunc func(*SearchCfg)
+
+func defaultSearchConfig() SearchCfg {
+	return SearchCfg{
+		depth: 4,
+	}
+}
+
+// WithDepth sets the maximum depth of the iterative deepening.
+func WithDepth(depth int) SearchCfgFunc {
+	return func(cfg *SearchCfg) {
+		cfg.depth = depth
+	}
+}
+
+// WithTimeLimit stops the search once the time is up. The best move of the
+// deepest completed iteration is returned. Zero means no limit.
+func WithTimeLimit(timeLimit time.Duration) SearchCfgFunc {
+	return func(cfg *SearchCfg) {
+		cfg.timeLimit = timeLimit
+	}
+}
+
+func WithEvaluator(eval Evaluator) SearchCfgFunc {
+	return func(cfg *SearchCfg) {
+		cfg.eval = eval
+	}
+}
+
+// WithSearchObjective makes the search play for objective. Without
+// WithEvaluator, FewestDiscs searches use AntiEval.
+func WithSearchObjective(objective Objective) SearchCfgFunc {
+	return func(cfg *SearchCfg) {
+		cfg.objective = objective
+	}
+}
+
+// WithTranspositionTable makes the search use tt, which may be shared with
+// other engines.
+func WithTranspositionTable(tt *TranspositionTable) SearchCfgFunc {
+	return func(cfg *SearchCfg) {
+		cfg.tt = tt
+	}
+}
+
+// Searcher picks moves with a negamax alpha-beta search.
+type Searcher struct {
+	cfg      SearchCfg
+	deadline time.Time
+	nodes    int
+	stopped  bool
+}
+
+func NewSearcher(cfgFuncs ...SearchCfgFunc) *Searcher {
+	cfg := defaultSearchConfig()
+	for _, cfgFunc := range cfgFuncs {
+		cfgFunc(&cfg)
+	}
+	if cfg.depth < 1 {
+		cfg.depth = 1
+	}
+	if cfg.eval == nil {
+		cfg.eval = HeuristicEval(DefaultEvalWeights)
+		if cfg.objective == FewestDiscs {
+			cfg.eval = AntiEval(DefaultEvalWeights)
+		}
+	}
+	if cfg.tt == nil {
+		cfg.tt = NewTranspositionTable(defaultTableSize)
+	}
+	return &Searcher{cfg: cfg}
+}
+
+// SearchResult is the outcome of a search. Score is from the side to move.
+type SearchResult struct {
+	Move  Point
+	Pass  bool
+	Score int
+	Depth int
+	Nodes int
+}
+
+// Search runs iterative deepening on p up to the configured depth or time
+// limit. Pass is set when the side to move has no legal move.
+func (s *Searcher) Search(p Position) SearchResult {
+	s.nodes, s.stopped = 0, false
+	s.cfg.tt.NewSearch()
+	if s.cfg.timeLimit > 0 {
+		s.deadline = time.Now().Add(s.cfg.timeLimit)
+	} else {
+		s.deadline = time.Time{}
+	}
+
+	moves := p.Moves()
+	if moves == 0 {
+		return SearchResult{Pass: true, Score: s.negamax(p, s.cfg.depth, -infinity, infinity)}
+	}
+
+	best := orderMoves(moves, -1)[0]
+	res := SearchResult{Move: squarePoint(best)}
+	for depth := 1; depth <= s.cfg.depth; depth++ {
+		move, score := s.searchRoot(p, depth, best)
+		if s.stopped {
+			break
+		}
+		best = move
+		res.Move, res.Score, res.Depth = squarePoint(move), score, depth
+	}
+	res.Nodes = s.nodes
+	return res
+}
+
+// BestMove searches p and returns the best move found.
+func (s *Searcher) BestMove(p Position) (Point, error) {
+	res := s.Search(p)
+	if res.Pass {
+		return Point{}, errors.New("no possible moves")
+	}
+	return res.Move, nil
+}
+
+// MoveScore is a legal move with its score from the side to move.
+type MoveScore struct {
+	Move  Point
+	Score int
+}
+
+// ScoreMoves searches every legal move of p to the configured depth, best
+// first. Unlike Search, each move gets its exact score rather than a bound,
+// which costs more nodes. The time limit does not apply.
+func (s *Searcher) ScoreMoves(p Position) []MoveScore {
+	s.nodes, s.stopped = 0, false
+	s.cfg.tt.NewSearch()
+	s.deadline = time.Time{}
+
+	var res []MoveScore
+	for _, sq := range orderMoves(p.Moves(), -1) {
+		score := -s.negamax(p.play(sq), s.cfg.depth-1, -infinity, infinity)
+		res = append(res, MoveScore{Move: squarePoint(sq), Score: score})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+	return res
+}
+
+func (s *Searcher) searchRoot(p Position, depth, first int) (int, int) {
+	alpha, beta := -infinity, infinity
+	best := first
+	for _, sq := range orderMoves(p.Moves(), first) {
+		score := -s.negamax(p.play(sq), depth-1, -beta, -alpha)
+		if s.stopped {
+			return best, alpha
+		}
+		if score > alpha {
+			alpha, best = score, sq
+		}
+	}
+	return best, alpha
+}
+
+func (s *Searcher) negamax(p Position, depth, alpha, beta int) int {
+	s.nodes++
+	if s.nodes%checkInterval == 0 && !s.deadline.IsZero() && time.Now().After(s.deadline) {
+		s.stopped = true
+	}
+	if s.stopped {
+		return 0
+	}
+
+	moves := p.Moves()
+	if moves == 0 {
+		if p.Pass().Moves() == 0 {
+			return finalScore(p, s.cfg.objective)
+		}
+		return -s.negamax(p.Pass(), depth, -beta, -alpha)
+	}
+	if depth <= 0 {
+		return s.cfg.eval(p)
+	}
+
+	key := p.Hash()
+	if s.cfg.objective == FewestDiscs {
+		key ^= fewestDiscsSalt
+	}
+	ttMove := -1
+	if e, ok := s.cfg.tt.Probe(key); ok {
+		ttMove = e.Move
+		if e.Depth >= depth {
+			switch e.Bound {
+			case ExactBound:
+				return e.Score
+			case LowerBound:
+				alpha = max(alpha, e.Score)
+			case UpperBound:
+				beta = min(beta, e.Score)
+			}
+			if alpha >= beta {
+				return e.Score
+			}
+		}
+	}
+
+	alphaOrig := alpha
+	best, bestMove := -infinity, -1
+	for _, sq := range orderMoves(moves, ttMove) {
+		score := -s.negamax(p.play(sq), depth-1, -beta, -alpha)
+		if score > best {
+			best, bestMove = score, sq
+		}
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	if s.stopped {
+		return 0
+	}
+
+	bound := ExactBound
+	if best <= alphaOrig {
+		bound = UpperBound
+	} else if best >= beta {
+		bound = LowerBound
+	}
+	s.cfg.tt.Store(key, TTEntry{Depth: depth, Score: best, Bound: bound, Move: bestMove})
+	return best
+}
+
+// finalScore scores a finished game from the side to move.
+func finalScore(p Position, objective Objective) int {
+	diff := objective.sign() * p.DiscDiff()
+	switch {
+	case diff > 0:
+		return winScore + diff
+	case diff < 0:
+		return -winScore + diff
+	}
+	return 0
+}
+
+// orderMoves lists the moves by squareOrder, best first. The move first, if
+// present, is put in front.
+func orderMoves(moves uint64, first int) []int {
+	res := make([]int, 0, bits.OnesCount64(moves))
+	for ; moves != 0; moves &= moves - 1 {
+		res = append(res, bits.TrailingZeros64(moves))
+	}
+	for i := 1; i < len(res); i++ {
+		for j := i; j > 0 && moveRank(res[j], first) > moveRank(res[j-1], first); j-- {
+			res[j], res[j-1] = res[j-1], res[j]
+		}
+	}
+	return res
+}
+
+func moveRank(sq, first int) int {
+	if sq == first {
+		return len(squareOrder)
+	}
+	return squareOrder[sq]
+}
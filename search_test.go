@@ -0,0 +1,151 @@
+package reversi
+
+import (
+	"testing"
+	"time"
+)
+
+// positionFromGrid builds the Position of token from a board literal.
+func positionFromGrid(t *testing.T, board [][]int, token int) Position {
+	t.Helper()
+	p, err := PositionFromBoard(board, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestSearchTakesCorner(t *testing.T) {
+	p := positionFromGrid(t, [][]int{
+		{0, 2, 2, 1, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 1, 2, 0, 0, 0},
+		{0, 0, 0, 2, 1, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+	}, 1)
+
+	for depth := 1; depth <= 4; depth++ {
+		if got, want := NewSearcher(WithDepth(depth)).Search(p).Move, (Point{0, 0}); got != want {
+			t.Errorf("Search(depth %v), want: %v, got %v", depth, want, got)
+		}
+	}
+}
+
+func TestSearchFindsWipeout(t *testing.T) {
+	// Playing a1 flips every opponent disc and ends the game.
+	p := positionFromGrid(t, [][]int{
+		{0, 2, 1, 0, 0, 0, 0, 0},
+		{2, 2, 0, 0, 0, 0, 0, 0},
+		{1, 0, 1, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+	}, 1)
+
+	res := NewSearcher(WithDepth(3)).Search(p)
+	if got, want := res.Move, (Point{0, 0}); got != want {
+		t.Errorf("Search().Move, want: %v, got %v", want, got)
+	}
+	if res.Score < winScore {
+		t.Errorf("Search().Score, want: >= %v, got %v", winScore, res.Score)
+	}
+}
+
+func TestSearchFewestDiscs(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		// Searching past the end of the game finds the best anti-reversi move.
+		p := randomPosition(seed, 7)
+		res := NewSearcher(WithDepth(20), WithSearchObjective(FewestDiscs)).Search(p)
+		if res.Pass {
+			continue
+		}
+		if got, want := -minimaxFor(p.Play(res.Move), FewestDiscs), minimaxFor(p, FewestDiscs); got != want {
+			t.Errorf("seed %v: Search() under FewestDiscs plays %v scoring %v, want: %v", seed, res.Move, got, want)
+		}
+	}
+}
+
+func TestSearchPass(t *testing.T) {
+	p := positionFromGrid(t, [][]int{
+		{1, 2, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0, 0, 0},
+	}, 2)
+
+	if res := NewSearcher().Search(p); !res.Pass {
+		t.Errorf("Search().Pass, want: true, got %v", res)
+	}
+}
+
+func TestSearchTimeLimit(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	start := time.Now()
+	res := NewSearcher(WithDepth(60), WithTimeLimit(50*time.Millisecond)).Search(g.Position(1))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Search() took %v, want about 50ms", elapsed)
+	}
+	if !g.P1().CanMove(res.Move) || res.Depth == 0 {
+		t.Errorf("Search(), want a legal move from a completed depth, got %v", res)
+	}
+}
+
+func TestComputerChooseMove(t *testing.T) {
+	for level := MinLevel; level <= MaxLevel; level++ {
+		p := NewPlayer(1, WithPlayerType(Computer), WithLevel(level))
+		g := NewGameBoard(*p, *NewPlayer(2))
+		move, err := g.P1().ChooseMove(g)
+		if err != nil || !g.P1().CanMove(move) {
+			t.Errorf("level %v: ChooseMove(), want a legal move, got (%v, %v)", level, move, err)
+		}
+	}
+
+	for _, size := range []int{8, 10} {
+		p := NewPlayer(1, WithPlayerType(Computer), WithLevel(2))
+		g := NewGameBoard(*p, *NewPlayer(2), WithSize(size), WithObjective(FewestDiscs))
+		move, err := g.P1().ChooseMove(g)
+		if err != nil || !g.P1().CanMove(move) {
+			t.Errorf("%vx%v FewestDiscs: ChooseMove(), want a legal move, got (%v, %v)", size, size, move, err)
+		}
+	}
+}
+
+// countingEngine counts the moves it is asked for.
+type countingEngine struct {
+	Engine
+	calls int
+}
+
+func (e *countingEngine) BestMove(pos Position) (Point, error) {
+	e.calls++
+	return e.Engine.BestMove(pos)
+}
+
+func TestComputerChooseMoveFewestDiscs(t *testing.T) {
+	engine := &countingEngine{Engine: NewSearcher(WithDepth(1), WithSearchObjective(FewestDiscs))}
+	p := NewPlayer(1, WithPlayerType(Computer), WithEngine(engine))
+	g := NewGameBoard(*p, *NewPlayer(2), WithObjective(FewestDiscs))
+	if _, err := g.P1().ChooseMove(g); err != nil || engine.calls != 1 {
+		t.Errorf("FewestDiscs with WithEngine, want the engine called once, got %v calls (%v)", engine.calls, err)
+	}
+
+	evals := 0
+	eval := func(pos Position) int {
+		evals++
+		return 0
+	}
+	p = NewPlayer(1, WithPlayerType(Computer), WithLevel(1), WithEvaluation(eval))
+	g = NewGameBoard(*p, *NewPlayer(2), WithObjective(FewestDiscs))
+	if _, err := g.P1().ChooseMove(g); err != nil || evals == 0 {
+		t.Errorf("FewestDiscs with WithEvaluation, want the evaluation used, got %v calls (%v)", evals, err)
+	}
+}
@@ -0,0 +1,348 @@
+package reversi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"math/rand"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Sample is a position of a self-play game with what became of it.
+type Sample struct {
+	Position Position
+	// Outcome is the final disc difference of the side to move.
+	Outcome int
+	// Score is the search score of the side to move, in the units of the
+	// evaluator of the search.
+	Score int
+}
+
+type SelfPlayCfg struct {
+	search      []SearchCfgFunc
+	randomMoves int
+	randomRate  float64
+	workers     int
+	seed        int64
+}
+
+type SelfPlayCfgFunc func(cfg *SelfPlayCfg)
+
+// WithSelfPlaySearch sets up the engine playing both sides, a depth 4
+// search by default.
+func WithSelfPlaySearch(cfgFuncs ...SearchCfgFunc) SelfPlayCfgFunc {
+	return func(cfg *SelfPlayCfg) {
+		cfg.search = cfgFuncs
+	}
+}
+
+// WithSelfPlayRandomMoves opens every game with moves random moves, 10 by
+// default. Their positions are left out of the samples.
+func WithSelfPlayRandomMoves(moves int) SelfPlayCfgFunc {
+	return func(cfg *SelfPlayCfg) {
+		cfg.randomMoves = moves
+	}
+}
+
+// WithSelfPlayRandomRate plays a random move instead of the best one with
+// probability rate after the opening, 0.05 by default.
+func WithSelfPlayRandomRate(rate float64) SelfPlayCfgFunc {
+	return func(cfg *SelfPlayCfg) {
+		cfg.randomRate = rate
+	}
+}
+
+// WithSelfPlayWorkers sets the number of games played at once, the number of
+// CPUs by default.
+func WithSelfPlayWorkers(workers int) SelfPlayCfgFunc {
+	return func(cfg *SelfPlayCfg) {
+		cfg.workers = workers
+	}
+}
+
+// WithSelfPlaySeed seeds the random moves, so the games can be played again.
+func WithSelfPlaySeed(seed int64) SelfPlayCfgFunc {
+	return func(cfg *SelfPlayCfg) {
+		cfg.seed = seed
+	}
+}
+
+// SelfPlay plays games of the engine against itself in parallel and hands
+// the samples of each game to onGame, from one goroutine at a time and in no
+// particular order. Every position where a move was searched is a sample.
+// It stops at the first error of onGame.
+func SelfPlay(games int, onGame func([]Sample) error, cfgFuncs ...SelfPlayCfgFunc) error {
+	cfg := SelfPlayCfg{
+		search:      []SearchCfgFunc{WithDepth(4)},
+		randomMoves: 10,
+		randomRate:  0.05,
+		workers:     runtime.NumCPU(),
+		seed:        time.Now().UnixNano(),
+	}
+	for _, cfgFunc := range cfgFuncs {
+		cfgFunc(&cfg)
+	}
+	if cfg.workers < 1 {
+		cfg.workers = 1
+	}
+
+	jobs := make(chan int)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var err error
+	for w := 0; w < cfg.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := NewSearcher(cfg.search...)
+			for i := range jobs {
+				samples := selfPlayGame(s, rand.New(rand.NewSource(cfg.seed+int64(i))), cfg)
+				mu.Lock()
+				if err == nil {
+					if err = onGame(samples); err != nil {
+						close(done)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for i := 0; i < games; i++ {
+		select {
+		case jobs <- i:
+		case <-done:
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return err
+}
+
+// selfPlayGame plays a game of s against itself and returns its samples.
+func selfPlayGame(s *Searcher, rng *rand.Rand, cfg SelfPlayCfg) []Sample {
+	var samples []Sample
+	// turns holds the turn of each sample, counting passes, to tell whose
+	// side it was on.
+	var turns []int
+	p, turn := startPosition(), 0
+	for ; !p.GameOver(); turn++ {
+		moves := p.Moves()
+		if moves == 0 {
+			p = p.Pass()
+			continue
+		}
+		var sq int
+		if turn < cfg.randomMoves {
+			sq = randomSquare(rng, moves)
+		} else {
+			res := s.Search(p)
+			samples = append(samples, Sample{Position: p, Score: res.Score})
+			turns = append(turns, turn)
+			sq = res.Move.Y*Width + res.Move.X
+			if rng.Float64() < cfg.randomRate {
+				sq = randomSquare(rng, moves)
+			}
+		}
+		p = p.play(sq)
+	}
+	for i := range samples {
+		samples[i].Outcome = p.DiscDiff()
+		if (turn-turns[i])%2 != 0 {
+			samples[i].Outcome = -samples[i].Outcome
+		}
+	}
+	return samples
+}
+
+// randomSquare returns one of the squares of moves at random.
+func randomSquare(rng *rand.Rand, moves uint64) int {
+	for n := rng.Intn(bits.OnesCount64(moves)); n > 0; n-- {
+		moves &= moves - 1
+	}
+	return bits.TrailingZeros64(moves)
+}
+
+// DatasetFormat is the file format of samples.
+type DatasetFormat int
+
+const (
+	// BinaryDataset writes a header then each sample in 21 bytes: the Own
+	// and Opp bitboards as little-endian uint64, Score as int32 and Outcome
+	// as int8.
+	BinaryDataset DatasetFormat = iota
+	// CSVDataset writes a header then a line per sample with the position
+	// string of the side to move as X (see GameBoard.String), the number of
+	// empty cells, the outcome and the score.
+	CSVDataset
+)
+
+const sampleSize = 21
+
+// datasetMagic starts the binary datasets.
+var datasetMagic = []byte("RVDS")
+
+var datasetHeader = []string{"position", "empties", "outcome", "score"}
+
+// SampleWriter writes samples to a dataset.
+type SampleWriter struct {
+	w      *bufio.Writer
+	csv    *csv.Writer
+	header bool
+}
+
+// NewSampleWriter returns a SampleWriter writing to w in format. Call Flush
+// once done.
+func NewSampleWriter(w io.Writer, format DatasetFormat) *SampleWriter {
+	sw := &SampleWriter{w: bufio.NewWriter(w)}
+	if format == CSVDataset {
+		sw.csv = csv.NewWriter(sw.w)
+	}
+	return sw
+}
+
+// Write adds s to the dataset.
+func (sw *SampleWriter) Write(s Sample) error {
+	if sw.csv != nil {
+		if !sw.header {
+			sw.header = true
+			if err := sw.csv.Write(datasetHeader); err != nil {
+				return err
+			}
+		}
+		return sw.csv.Write([]string{
+			samplePositionString(s.Position),
+			strconv.Itoa(s.Position.Empties()),
+			strconv.Itoa(s.Outcome),
+			strconv.Itoa(s.Score),
+		})
+	}
+	if !sw.header {
+		sw.header = true
+		if _, err := sw.w.Write(datasetMagic); err != nil {
+			return err
+		}
+	}
+	var rec [sampleSize]byte
+	binary.LittleEndian.PutUint64(rec[0:8], s.Position.Own)
+	binary.LittleEndian.PutUint64(rec[8:16], s.Position.Opp)
+	binary.LittleEndian.PutUint32(rec[16:20], uint32(int32(s.Score)))
+	rec[20] = byte(int8(s.Outcome))
+	_, err := sw.w.Write(rec[:])
+	return err
+}
+
+// Flush writes the buffered samples.
+func (sw *SampleWriter) Flush() error {
+	if sw.csv != nil {
+		sw.csv.Flush()
+		if err := sw.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return sw.w.Flush()
+}
+
+// samplePositionString returns the position string of p with the side to
+// move as X.
+func samplePositionString(p Position) string {
+	b := make([]byte, 0, Width*Height+2)
+	for sq := 0; sq < Width*Height; sq++ {
+		switch {
+		case p.Own&(1<<sq) != 0:
+			b = append(b, positionCells[1])
+		case p.Opp&(1<<sq) != 0:
+			b = append(b, positionCells[2])
+		default:
+			b = append(b, positionCells[0])
+		}
+	}
+	return string(append(b, ' ', positionCells[1]))
+}
+
+// ReadSamples reads a dataset written by SampleWriter in either format.
+func ReadSamples(r io.Reader) ([]Sample, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(datasetMagic)); bytes.Equal(magic, datasetMagic) {
+		br.Discard(len(datasetMagic))
+		return readBinarySamples(br)
+	}
+	return readCSVSamples(br)
+}
+
+func readBinarySamples(r io.Reader) ([]Sample, error) {
+	var samples []Sample
+	var rec [sampleSize]byte
+	for {
+		if _, err := io.ReadFull(r, rec[:]); err == io.EOF {
+			return samples, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("sample %d: %w", len(samples)+1, err)
+		}
+		s := Sample{
+			Position: Position{
+				Own: binary.LittleEndian.Uint64(rec[0:8]),
+				Opp: binary.LittleEndian.Uint64(rec[8:16]),
+			},
+			Score:   int(int32(binary.LittleEndian.Uint32(rec[16:20]))),
+			Outcome: int(int8(rec[20])),
+		}
+		if s.Position.Own&s.Position.Opp != 0 {
+			return nil, fmt.Errorf("sample %d: overlapping discs", len(samples)+1)
+		}
+		samples = append(samples, s)
+	}
+}
+
+func readCSVSamples(r io.Reader) ([]Sample, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	// line is the line number of the first record.
+	line := 1
+	if len(records) > 0 && records[0][0] == datasetHeader[0] {
+		records, line = records[1:], 2
+	}
+	samples := make([]Sample, 0, len(records))
+	for i, rec := range records {
+		if len(rec) != len(datasetHeader) {
+			return nil, fmt.Errorf("line %d: want %d fields, got %d", line+i, len(datasetHeader), len(rec))
+		}
+		g, err := ParsePosition(rec[0], *NewPlayer(1), *NewPlayer(2))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+i, err)
+		}
+		if !g.standard() {
+			return nil, fmt.Errorf("line %d: not a standard board", line+i)
+		}
+		outcome, err1 := strconv.Atoi(rec[2])
+		score, err2 := strconv.Atoi(rec[3])
+		if err := errors.Join(err1, err2); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+i, err)
+		}
+		samples = append(samples, Sample{Position: g.Position(g.CurrentPlayer().Token()), Outcome: outcome, Score: score})
+	}
+	return samples, nil
+}
+
+// LoadSamplesFile reads the dataset of the named file.
+func LoadSamplesFile(name string) ([]Sample, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadSamples(f)
+}
@@ -0,0 +1,134 @@
+package reversi
+
+import (
+	"bytes"
+	"errors"
+	"math/bits"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelfPlay(t *testing.T) {
+	var games [][]Sample
+	play := func() [][]Sample {
+		games = nil
+		err := SelfPlay(3, func(s []Sample) error {
+			games = append(games, s)
+			return nil
+		}, WithSelfPlaySearch(WithDepth(2)), WithSelfPlayRandomMoves(6), WithSelfPlayWorkers(1), WithSelfPlaySeed(5))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return games
+	}
+
+	first := play()
+	if len(first) != 3 {
+		t.Fatalf("games, want: 3, got %v", len(first))
+	}
+	for i, samples := range first {
+		if len(samples) == 0 {
+			t.Fatalf("game %d, want samples", i)
+		}
+		if got, want := samples[0].Position.Empties(), Width*Height-4-6; got != want {
+			t.Errorf("game %d first sample, want %v empties, got %v", i, want, got)
+		}
+		for j, s := range samples {
+			if s.Position.Moves() == 0 {
+				t.Errorf("game %d sample %d, want a position with moves", i, j)
+			}
+			if j > 0 {
+				if want := nextOutcome(samples[j-1], s.Position); s.Outcome != want {
+					t.Errorf("game %d sample %d, want outcome %v, got %v", i, j, want, s.Outcome)
+				}
+			}
+		}
+	}
+	if again := play(); !reflect.DeepEqual(again, first) {
+		t.Error("SelfPlay() with the same seed, want the same games")
+	}
+
+	calls := 0
+	errStop := errors.New("stop")
+	err := SelfPlay(10, func([]Sample) error {
+		calls++
+		return errStop
+	}, WithSelfPlaySearch(WithDepth(1)))
+	if !errors.Is(err, errStop) || calls != 1 {
+		t.Errorf("SelfPlay() with a failing onGame, want %v after 1 call, got %v after %v", errStop, err, calls)
+	}
+}
+
+// nextOutcome returns the outcome of p, played one move after prev, with
+// the sides alternating unless the opponent passed.
+func nextOutcome(prev Sample, p Position) int {
+	for moves := prev.Position.Moves(); moves != 0; moves &= moves - 1 {
+		next := prev.Position.play(bits.TrailingZeros64(moves))
+		if next == p {
+			return -prev.Outcome
+		}
+		if next.Pass() == p {
+			return prev.Outcome
+		}
+	}
+	return 0
+}
+
+func TestSampleWriter(t *testing.T) {
+	samples := []Sample{
+		{Position: startPosition(), Outcome: -64, Score: -100020},
+		{Position: startPosition().play(19), Outcome: 12, Score: 345},
+	}
+	tests := []struct {
+		name   string
+		format DatasetFormat
+		size   int
+	}{
+		{"binary", BinaryDataset, len(datasetMagic) + 2*sampleSize},
+		{"csv", CSVDataset, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewSampleWriter(&buf, tt.format)
+			for _, s := range samples {
+				if err := w.Write(s); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if tt.size > 0 && buf.Len() != tt.size {
+				t.Errorf("size, want: %v, got %v", tt.size, buf.Len())
+			}
+			got, err := ReadSamples(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, samples) {
+				t.Errorf("ReadSamples(), want: %v, got %v", samples, got)
+			}
+		})
+	}
+}
+
+func TestReadSamplesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"truncated", "RVDS\x01\x02"},
+		{"bad position", "position,empties,outcome,score\n---X,60,0,0\n"},
+		{"bad outcome", "position,empties,outcome,score\n" + samplePositionString(startPosition()) + ",60,win,0\n"},
+		{"missing field", samplePositionString(startPosition()) + ",60,0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadSamples(strings.NewReader(tt.data)); err == nil {
+				t.Error("ReadSamples(), want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package reversi
+
+import "math/bits"
+
+// flipVertical mirrors the rows: y becomes Height-1-y.
+func flipVertical(b uint64) uint64 {
+	return bits.ReverseBytes64(b)
+}
+
+// mirrorHorizontal mirrors the columns: x becomes Width-1-x.
+func mirrorHorizontal(b uint64) uint64 {
+	const (
+		k1 = 0x5555555555555555
+		k2 = 0x3333333333333333
+		k4 = 0x0f0f0f0f0f0f0f0f
+	)
+	b = (b>>1)&k1 | (b&k1)<<1
+	b = (b>>2)&k2 | (b&k2)<<2
+	b = (b>>4)&k4 | (b&k4)<<4
+	return b
+}
+
+// transpose swaps x and y.
+func transpose(b uint64) uint64 {
+	const (
+		k1 = 0x5500550055005500
+		k2 = 0x3333000033330000
+		k4 = 0x0f0f0f0f00000000
+	)
+	t := k4 & (b ^ (b << 28))
+	b ^= t ^ (t >> 28)
+	t = k2 & (b ^ (b << 14))
+	b ^= t ^ (t >> 14)
+	t = k1 & (b ^ (b << 7))
+	b ^= t ^ (t >> 7)
+	return b
+}
+
+// symmetry returns b under the i-th of the 8 symmetries of the board, i in
+// [0, 8). Bit 0 mirrors the columns, bit 1 the rows and bit 2 transposes.
+func symmetry(b uint64, i int) uint64 {
+	if i&1 != 0 {
+		b = mirrorHorizontal(b)
+	}
+	if i&2 != 0 {
+		b = flipVertical(b)
+	}
+	if i&4 != 0 {
+		b = transpose(b)
+	}
+	return b
+}
+
+// Canonical returns the representative of p among its 8 symmetric positions,
+// so that rotated or mirrored positions compare equal.
+func (p Position) Canonical() Position {
+	best := p
+	for i := 1; i < 8; i++ {
+		q := Position{Own: symmetry(p.Own, i), Opp: symmetry(p.Opp, i)}
+		if q.Own < best.Own || q.Own == best.Own && q.Opp < best.Opp {
+			best = q
+		}
+	}
+	return best
+}
@@ -0,0 +1,415 @@
+package reversi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// TournamentFormat decides who plays whom in a tournament.
+type TournamentFormat int
+
+const (
+	// RoundRobin pairs every entrant with every other.
+	RoundRobin TournamentFormat = iota
+	// Gauntlet pairs the first entrant with each of the others.
+	Gauntlet
+)
+
+const (
+	// DefaultTournamentGames is the number of games per pairing unless
+	// WithTournamentGames says otherwise.
+	DefaultTournamentGames = 10
+	// eloPrior is the number of draws each entrant is given against every
+	// opponent, so ratings stay finite after a clean sweep.
+	eloPrior = 1
+)
+
+// Entrant is an engine configuration taking part in a tournament. It plays
+// as a Computer player of Level, searching with Eval if set, or with the
+// engine of NewEngine if set. A new engine is made for every game and closed
+// after it if it is an io.Closer, so external engines can run in parallel
+// games.
+type Entrant struct {
+	Name      string
+	Level     int
+	Eval      Evaluator
+	NewEngine func() (Engine, error)
+}
+
+// player makes the player of e holding token for one game, and the function
+// releasing its engine.
+func (e Entrant) player(token int) (*Player, func(), error) {
+	opts := []PlayerCfgFunc{WithPlayerType(Computer), WithLevel(e.Level), WithName(e.Name)}
+	if e.Eval != nil {
+		opts = append(opts, WithEvaluation(e.Eval))
+	}
+	release := func() {}
+	if e.NewEngine != nil {
+		engine, err := e.NewEngine()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v: %w", e.Name, err)
+		}
+		opts = append(opts, WithEngine(engine))
+		if c, ok := engine.(io.Closer); ok {
+			release = func() { c.Close() }
+		}
+	}
+	return NewPlayer(token, opts...), release, nil
+}
+
+type TournamentCfg struct {
+	format       TournamentFormat
+	games        int
+	openingMoves int
+	workers      int
+	seed         int64
+	onGame       func(TournamentGame)
+}
+
+type TournamentCfgFunc func(cfg *TournamentCfg)
+
+// WithTournamentFormat sets the pairings, RoundRobin by default.
+func WithTournamentFormat(format TournamentFormat) TournamentCfgFunc {
+	return func(cfg *TournamentCfg) {
+		cfg.format = format
+	}
+}
+
+// WithTournamentGames sets the number of games of each pairing. Games come in
+// pairs from the same opening with the other entrant moving first, so an odd
+// number leaves the last opening played once.
+func WithTournamentGames(games int) TournamentCfgFunc {
+	return func(cfg *TournamentCfg) {
+		cfg.games = games
+	}
+}
+
+// WithTournamentOpenings starts the games from balanced openings of the given
+// number of moves, see RandomBalancedLayout. Zero starts every game from the
+// starting position.
+func WithTournamentOpenings(moves int) TournamentCfgFunc {
+	return func(cfg *TournamentCfg) {
+		cfg.openingMoves = moves
+	}
+}
+
+// WithTournamentWorkers sets the number of games played at once, the number
+// of CPUs by default.
+func WithTournamentWorkers(workers int) TournamentCfgFunc {
+	return func(cfg *TournamentCfg) {
+		cfg.workers = workers
+	}
+}
+
+// WithTournamentSeed seeds the openings, so tournaments can be repeated.
+func WithTournamentSeed(seed int64) TournamentCfgFunc {
+	return func(cfg *TournamentCfg) {
+		cfg.seed = seed
+	}
+}
+
+// WithTournamentProgress calls onGame after every game, from one goroutine
+// at a time.
+func WithTournamentProgress(onGame func(TournamentGame)) TournamentCfgFunc {
+	return func(cfg *TournamentCfg) {
+		cfg.onGame = onGame
+	}
+}
+
+// TournamentGame is a game of a tournament between the entrants of index P1
+// and P2, holding tokens 1 and 2.
+type TournamentGame struct {
+	P1, P2 int
+	// Opening is the index of the opening the game started from.
+	Opening int
+	// P1First is set when P1 moved first.
+	P1First bool
+	// Score is the result for P1: 1 for a win, 0.5 for a draw, 0 for a loss.
+	Score float64
+	// Discs holds the final disc counts of P1 and P2.
+	Discs [2]int
+	// GGF is the record of the game.
+	GGF string
+	// Err is set when an engine failed. Its player lost the game.
+	Err error
+}
+
+// TournamentResult holds the games of a tournament in the order they were
+// scheduled.
+type TournamentResult struct {
+	Entrants []Entrant
+	Games    []TournamentGame
+}
+
+// RunTournament plays the games between entrants, in parallel. Each pairing
+// plays from the same openings, every opening twice with the other entrant
+// moving first, alternating like the rounds of a room.
+func RunTournament(entrants []Entrant, cfgFuncs ...TournamentCfgFunc) (TournamentResult, error) {
+	cfg := TournamentCfg{
+		games:   DefaultTournamentGames,
+		workers: runtime.NumCPU(),
+		seed:    time.Now().UnixNano(),
+	}
+	for _, cfgFunc := range cfgFuncs {
+		cfgFunc(&cfg)
+	}
+	if len(entrants) < 2 {
+		return TournamentResult{}, errors.New("a tournament needs two entrants or more")
+	}
+	if cfg.games < 1 {
+		return TournamentResult{}, fmt.Errorf("a pairing plays one game or more, not %d", cfg.games)
+	}
+	if cfg.workers < 1 {
+		cfg.workers = 1
+	}
+
+	openings := make([][][]int, (cfg.games+1)/2)
+	if cfg.openingMoves > 0 {
+		rng := rand.New(rand.NewSource(cfg.seed))
+		for i := range openings {
+			openings[i] = RandomBalancedLayout(rng, cfg.openingMoves)
+		}
+	}
+
+	var games []TournamentGame
+	for i := range entrants {
+		for j := i + 1; j < len(entrants); j++ {
+			if cfg.format == Gauntlet && i > 0 {
+				break
+			}
+			for round := 0; round < cfg.games; round++ {
+				games = append(games, TournamentGame{
+					P1:      i,
+					P2:      j,
+					Opening: round / 2,
+					P1First: (round+1)%2 == 1,
+				})
+			}
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for w := 0; w < cfg.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				g := &games[i]
+				playTournamentGame(entrants, g, openings[g.Opening])
+				if cfg.onGame != nil {
+					mu.Lock()
+					cfg.onGame(*g)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range games {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return TournamentResult{Entrants: entrants, Games: games}, nil
+}
+
+// playTournamentGame plays g from layout, the starting position if nil,
+// drawn with player 1 to move.
+func playTournamentGame(entrants []Entrant, g *TournamentGame, layout [][]int) {
+	p1, release1, err := entrants[g.P1].player(1)
+	if err != nil {
+		g.Err, g.Score = err, 0
+		return
+	}
+	defer release1()
+	p2, release2, err := entrants[g.P2].player(2)
+	if err != nil {
+		g.Err, g.Score = err, 1
+		return
+	}
+	defer release2()
+
+	cfgFuncs := []GameCfgFunc{WithP1First(g.P1First)}
+	if layout != nil {
+		if !g.P1First {
+			layout = swapLayout(layout)
+		}
+		cfgFuncs = append(cfgFuncs, WithLayout(layout))
+	}
+	board := NewGameBoard(*p1, *p2, cfgFuncs...)
+	for !board.EndGame() {
+		if board.MustPass() {
+			board.Pass()
+			continue
+		}
+		p := board.CurrentPlayer()
+		move, err := p.ChooseMove(board)
+		if err == nil {
+			_, err = board.Mark(move, *p)
+		}
+		if err != nil {
+			g.Err = fmt.Errorf("%v: %w", p.Name(), err)
+			board.Surrender(p)
+			break
+		}
+	}
+
+	g.Discs = [2]int{board.P1().Score(), board.P2().Score()}
+	switch winner := board.Result(); {
+	case winner == nil:
+		g.Score = 0.5
+	case winner.Token() == 1:
+		g.Score = 1
+	default:
+		g.Score = 0
+	}
+	g.GGF = board.GGF(GameInfo{Place: "tournament", Date: time.Now()})
+}
+
+// swapLayout returns layout with the discs of the two players swapped.
+func swapLayout(layout [][]int) [][]int {
+	res := make([][]int, len(layout))
+	for y, row := range layout {
+		res[y] = make([]int, len(row))
+		for x, cell := range row {
+			res[y][x] = cell
+			if cell > 0 {
+				res[y][x] = 3 - cell
+			}
+		}
+	}
+	return res
+}
+
+// Crosstable returns the points scored by each entrant against each other,
+// indexed [entrant][opponent], and the number of games they played.
+func (r TournamentResult) Crosstable() (points [][]float64, games [][]int) {
+	n := len(r.Entrants)
+	points, games = make([][]float64, n), make([][]int, n)
+	for i := range points {
+		points[i], games[i] = make([]float64, n), make([]int, n)
+	}
+	for _, g := range r.Games {
+		points[g.P1][g.P2] += g.Score
+		points[g.P2][g.P1] += 1 - g.Score
+		games[g.P1][g.P2]++
+		games[g.P2][g.P1]++
+	}
+	return points, games
+}
+
+// Rating is the Elo estimate of an entrant. The true rating lies within
+// Margin of Elo with 95% confidence.
+type Rating struct {
+	Elo    float64
+	Margin float64
+	Points float64
+	Games  int
+}
+
+// Ratings estimates the Elo of every entrant from the results, by maximum
+// likelihood under the Bradley-Terry model. Ratings average to 0, and each
+// entrant gets eloPrior draws against every opponent it met so that clean
+// sweeps keep finite ratings. Margins follow from the spread of the results
+// of each entrant, prior draws included.
+func (r TournamentResult) Ratings() []Rating {
+	n := len(r.Entrants)
+	points, games := r.Crosstable()
+	wins := make([]float64, n)
+	played := make([][]float64, n)
+	for i := range played {
+		played[i] = make([]float64, n)
+		for j := range played[i] {
+			if games[i][j] > 0 {
+				played[i][j] = float64(games[i][j] + eloPrior)
+				wins[i] += points[i][j] + eloPrior/2.0
+			}
+		}
+	}
+
+	// Minorization-maximization of the strengths gamma.
+	gamma := make([]float64, n)
+	for i := range gamma {
+		gamma[i] = 1
+	}
+	for iter := 0; iter < 1000; iter++ {
+		maxDelta := 0.0
+		for i := range gamma {
+			denom := 0.0
+			for j := range gamma {
+				if played[i][j] > 0 {
+					denom += played[i][j] / (gamma[i] + gamma[j])
+				}
+			}
+			if denom == 0 {
+				continue
+			}
+			next := wins[i] / denom
+			maxDelta = math.Max(maxDelta, math.Abs(next-gamma[i])/gamma[i])
+			gamma[i] = next
+		}
+		if maxDelta < 1e-9 {
+			break
+		}
+	}
+
+	res := make([]Rating, n)
+	mean := 0.0
+	for i := range res {
+		res[i].Elo = 400 * math.Log10(gamma[i])
+		mean += res[i].Elo / float64(n)
+	}
+	scores := make([][]float64, n)
+	for _, g := range r.Games {
+		scores[g.P1] = append(scores[g.P1], g.Score)
+		scores[g.P2] = append(scores[g.P2], 1-g.Score)
+	}
+	for i := range res {
+		res[i].Elo -= mean
+		res[i].Games = len(scores[i])
+		for _, s := range scores[i] {
+			res[i].Points += s
+		}
+		// The prior draws keep the margin of a clean sweep from vanishing.
+		for j := range games[i] {
+			for k := 0; k < eloPrior && games[i][j] > 0; k++ {
+				scores[i] = append(scores[i], 0.5)
+			}
+		}
+		res[i].Margin = eloMargin(scores[i])
+	}
+	return res
+}
+
+// eloMargin returns half the width of the 95% confidence interval of the Elo
+// difference implied by scores, the results of one entrant.
+func eloMargin(scores []float64) float64 {
+	n := float64(len(scores))
+	if n < 2 {
+		return math.Inf(1)
+	}
+	mean := 0.0
+	for _, s := range scores {
+		mean += s / n
+	}
+	variance := 0.0
+	for _, s := range scores {
+		variance += (s - mean) * (s - mean) / n
+	}
+	dev := 1.96 * math.Sqrt(variance/n)
+	return (eloDiff(mean+dev) - eloDiff(mean-dev)) / 2
+}
+
+// eloDiff returns the Elo difference expected to score p, kept finite at the
+// ends.
+func eloDiff(p float64) float64 {
+	p = math.Min(math.Max(p, 0.001), 0.999)
+	return -400 * math.Log10(1/p-1)
+}
@@ -0,0 +1,162 @@
+package reversi
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// failingEngine fails every move.
+type failingEngine struct{}
+
+func (failingEngine) BestMove(Position) (Point, error) {
+	return Point{}, errors.New("engine failure")
+}
+
+func TestRunTournament(t *testing.T) {
+	entrants := []Entrant{{Name: "a", Level: 1}, {Name: "b", Level: 2}, {Name: "c", Level: 1}}
+	tests := []struct {
+		name   string
+		format TournamentFormat
+		pairs  [][2]int
+	}{
+		{"round robin", RoundRobin, [][2]int{{0, 1}, {0, 2}, {1, 2}}},
+		{"gauntlet", Gauntlet, [][2]int{{0, 1}, {0, 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			played := 0
+			res, err := RunTournament(entrants,
+				WithTournamentFormat(tt.format),
+				WithTournamentGames(4),
+				WithTournamentOpenings(6),
+				WithTournamentWorkers(3),
+				WithTournamentSeed(1),
+				WithTournamentProgress(func(TournamentGame) { played++ }),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(res.Games), 4*len(tt.pairs); got != want || played != want {
+				t.Fatalf("games, want: %v, got %v played %v", want, got, played)
+			}
+			for i, g := range res.Games {
+				pair := tt.pairs[i/4]
+				if g.P1 != pair[0] || g.P2 != pair[1] {
+					t.Errorf("game %v, want: %v, got %v-%v", i, pair, g.P1, g.P2)
+				}
+				if want := i%2 == 0; g.P1First != want {
+					t.Errorf("game %v P1First, want: %v, got %v", i, want, g.P1First)
+				}
+				if want := i % 4 / 2; g.Opening != want {
+					t.Errorf("game %v opening, want: %v, got %v", i, want, g.Opening)
+				}
+				if g.Err != nil || !strings.HasPrefix(g.GGF, "(;GM[Othello]") {
+					t.Errorf("game %v, want a GGF record, got %q, %v", i, g.GGF, g.Err)
+				}
+			}
+		})
+	}
+}
+
+func TestRunTournamentOpenings(t *testing.T) {
+	// The two games of an opening start from the same discs, each entrant
+	// holding the colour to move once. Records mirror the board when player 2
+	// moves first.
+	res, err := RunTournament([]Entrant{{Name: "a", Level: 1}, {Name: "b", Level: 1}},
+		WithTournamentGames(2), WithTournamentOpenings(8), WithTournamentSeed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := func(g TournamentGame) Position {
+		board, _, err := ParseGGF(g.GGF)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for board.Undo() == nil {
+		}
+		return board.Position(board.CurrentPlayer().Token()).Canonical()
+	}
+	if a, b := first(res.Games[0]), first(res.Games[1]); a != b {
+		t.Errorf("openings, want the same position, got %v and %v", a, b)
+	}
+}
+
+func TestRunTournamentEngineFailure(t *testing.T) {
+	entrants := []Entrant{
+		{Name: "good", Level: 1},
+		{Name: "broken", NewEngine: func() (Engine, error) { return failingEngine{}, nil }},
+	}
+	res, err := RunTournament(entrants, WithTournamentGames(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, g := range res.Games {
+		if g.Err == nil || g.Score != 1 {
+			t.Errorf("game %v, want a forfeit by broken, got score %v, %v", i, g.Score, g.Err)
+		}
+	}
+
+	if _, err := RunTournament(entrants[:1]); err == nil {
+		t.Error("RunTournament() with one entrant, want an error")
+	}
+}
+
+func TestRatings(t *testing.T) {
+	entrants := make([]Entrant, 3)
+	var games []TournamentGame
+	add := func(p1, p2 int, score float64, n int) {
+		for i := 0; i < n; i++ {
+			games = append(games, TournamentGame{P1: p1, P2: p2, Score: score})
+		}
+	}
+	add(0, 1, 1, 6)
+	add(0, 1, 0, 2)
+	add(1, 2, 1, 6)
+	add(1, 2, 0.5, 2)
+	add(0, 2, 1, 8)
+	res := TournamentResult{Entrants: entrants, Games: games}
+
+	points, played := res.Crosstable()
+	if points[0][1] != 6 || points[1][0] != 2 || played[0][2] != 8 {
+		t.Errorf("Crosstable(), got %v %v", points, played)
+	}
+
+	ratings := res.Ratings()
+	sum := 0.0
+	for _, r := range ratings {
+		sum += r.Elo
+		if !(r.Margin > 0) || math.IsInf(r.Margin, 0) {
+			t.Errorf("margin, want a positive finite value, got %v", r.Margin)
+		}
+	}
+	if math.Abs(sum) > 1e-6 {
+		t.Errorf("ratings, want a mean of 0, got a sum of %v", sum)
+	}
+	if !(ratings[0].Elo > ratings[1].Elo && ratings[1].Elo > ratings[2].Elo) {
+		t.Errorf("ratings, want decreasing, got %+v", ratings)
+	}
+	if ratings[0].Points != 14 || ratings[0].Games != 16 {
+		t.Errorf("entrant 0, want: 14/16, got %v/%v", ratings[0].Points, ratings[0].Games)
+	}
+}
+
+func TestEloDiff(t *testing.T) {
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0.5, 0},
+		{0.75, 190.85},
+		{0.25, -190.85},
+	}
+	for _, tt := range tests {
+		if got := eloDiff(tt.p); math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("eloDiff(%v), want: %v, got %v", tt.p, tt.want, got)
+		}
+	}
+	if got := eloDiff(1); math.IsInf(got, 0) {
+		t.Errorf("eloDiff(1), want a finite value, got %v", got)
+	}
+}
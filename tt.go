@@ -0,0 +1,127 @@
+package reversi
+
+import "sync/atomic"
+
+// Bound tells how a stored score relates to the true score of a position.
+type Bound uint8
+
+const (
+	// NoBound marks an empty entry.
+	NoBound Bound = iota
+	// ExactBound means the score is the true score.
+	ExactBound
+	// LowerBound means the true score is at least the score (a cutoff).
+	LowerBound
+	// UpperBound means the true score is at most the score (all moves failed
+	// low).
+	UpperBound
+)
+
+// TTEntry is what the transposition table knows about a position. Move is the
+// best move as a cell index y*Width+x, or -1 if unknown.
+type TTEntry struct {
+	Depth int
+	Score int
+	Bound Bound
+	Move  int
+}
+
+// TranspositionTable remembers search results by position hash. It has a
+// fixed number of slots; when two positions fall in the same slot the deeper
+// or more recent search wins. It is safe for concurrent use, so the search
+// engines of several games may share one table.
+type TranspositionTable struct {
+	slots      []ttSlot
+	mask       uint64
+	generation atomic.Uint32
+}
+
+// ttSlot stores key^data next to data, so a slot torn by concurrent writes
+// fails the key check instead of returning mixed up data.
+type ttSlot struct {
+	check atomic.Uint64
+	data  atomic.Uint64
+}
+
+// NewTranspositionTable creates a table with at least size slots, rounded up
+// to a power of two.
+func NewTranspositionTable(size int) *TranspositionTable {
+	n := 1
+	for n < size {
+		n <<= 1
+	}
+	return &TranspositionTable{
+		slots: make([]ttSlot, n),
+		mask:  uint64(n - 1),
+	}
+}
+
+// NewSearch ages the entries so that positions of the previous searches are
+// replaced first.
+func (t *TranspositionTable) NewSearch() {
+	t.generation.Add(1)
+}
+
+// Clear empties the table.
+func (t *TranspositionTable) Clear() {
+	for i := range t.slots {
+		t.slots[i].check.Store(0)
+		t.slots[i].data.Store(0)
+	}
+}
+
+// Probe returns the entry stored for key.
+func (t *TranspositionTable) Probe(key uint64) (TTEntry, bool) {
+	s := &t.slots[key&t.mask]
+	data := s.data.Load()
+	if s.check.Load()^data != key {
+		return TTEntry{}, false
+	}
+	e := unpackTTEntry(data)
+	return e, e.Bound != NoBound
+}
+
+// Store saves an entry for key unless the slot holds a deeper search of
+// another position from the current generation.
+func (t *TranspositionTable) Store(key uint64, e TTEntry) {
+	s := &t.slots[key&t.mask]
+	gen := uint8(t.generation.Load())
+	old := s.data.Load()
+	if oldKey := s.check.Load() ^ old; oldKey != key && old != 0 {
+		oldEntry := unpackTTEntry(old)
+		if ttGeneration(old) == gen && oldEntry.Depth > e.Depth {
+			return
+		}
+	}
+	data := packTTEntry(e, gen)
+	s.data.Store(data)
+	s.check.Store(key ^ data)
+}
+
+// An entry is packed into 64 bits:
+//
+//	bits  0-31 score
+//	bits 32-39 depth
+//	bits 40-41 bound
+//	bits 42-48 move + 1
+//	bits 49-56 generation
+func packTTEntry(e TTEntry, gen uint8) uint64 {
+	return uint64(uint32(int32(e.Score))) |
+		uint64(uint8(e.Depth))<<32 |
+		uint64(e.Bound&3)<<40 |
+		uint64((e.Move+1)&0x7f)<<42 |
+		uint64(gen)<<49
+}
+
+func unpackTTEntry(data uint64) TTEntry {
+	return TTEntry{
+		Score: int(int32(uint32(data))),
+		Depth: int(uint8(data >> 32)),
+		Bound: Bound(data>>40) & 3,
+		Move:  int(data>>42&0x7f) - 1,
+	}
+}
+
+func ttGeneration(data uint64) uint8 {
+	return uint8(data >> 49)
+}
@@ -8,6 +8,11 @@ export interface Point {
   y: number;
 }
 
+export interface Bot {
+  id: string;
+  name: string;
+}
+
 export interface Room {
   roomUUID: string;
   name: string;
@@ -20,13 +25,15 @@ export enum ClientMessageType {
   LeaveRoom = "LEAVE_ROOM",
   StartGame = "START_GAME",
   MakeMove = "MAKE_MOVE",
+  Challenge = "CHALLENGE",
 }
 
 export type ClientMessage =
   | JoinRoomRequestMessage
   | LeaveRoomRequestMessage
   | StartGameMessage
-  | MakeMoveMessage;
+  | MakeMoveMessage
+  | ChallengeRequestMessage;
 
 export enum ServerMessageType {
   SendMessage = "SEND_MESSAGE",
@@ -37,6 +44,7 @@ export enum ServerMessageType {
   GameError = "GAME_ERROR",
   GameState = "GAME_STATE",
   GameResult = "GAME_RESULT",
+  BotUpdated = "BOT_UPDATED",
 }
 
 export type ServerMessage =
@@ -46,7 +54,9 @@ export type ServerMessage =
   | JoinRoomResponseMessage
   | LeaveRoomResponseMessage
   | GameErrorMessage
-  | GameStateMessage;
+  | GameStateMessage
+  | GameResultMessage
+  | BotUpdatedMessage;
 
 export interface Message {
   action: ServerMessageType.SendMessage;
@@ -71,6 +81,22 @@ export interface RegisterResponseMessage {
     id: string;
     name: string;
     rooms: Room[];
+    bots: Bot[]; // bots connected to the server
+  };
+}
+
+export interface BotUpdatedMessage {
+  action: ServerMessageType.BotUpdated;
+  message: Bot & { action: "JOINED" | "LEFT" };
+}
+
+// ChallengeRequestMessage challenges a bot to a game in the room of the
+// sender, who must be alone in it.
+export interface ChallengeRequestMessage {
+  action: ClientMessageType.Challenge;
+  message: {
+    botID: string;
+    game: StartGameMessage["message"];
   };
 }
 
@@ -93,9 +119,25 @@ export interface StartGameMessage {
   action: ClientMessageType.StartGame;
   message: {
     roomUUID: string;
+    position?: string; // position string to start from, see GameBoard.String
+    size?: number; // board size, 8 if not set
+    variant?: "standard" | "balanced" | "obstacles";
+    blocked?: Point[]; // cells to block
+    antiReversi?: boolean; // the player with fewer discs wins
+    handicap?: Handicap; // corner discs given before the first move
+    players?: number; // 2 to 4 players, 2 if not set
+    analysis?: boolean; // add the analysis to every game state
+    hints?: HintLevel; // help for the player to move, "moves" if not set
+    computer?: Computer; // seat a computer player in the last seat
   };
 }
 
+export interface Computer {
+  level?: number; // 1 to 5, 3 if not set
+  delayMs?: number; // least thinking time per move, 500 if not set
+  engine?: boolean; // play the external engine of the server
+}
+
 export interface GameErrorMessage {
   action: ServerMessageType.GameError;
   message: string;
@@ -107,6 +149,16 @@ export interface GameStatePlayer {
   token: number;
   score: number;
   possibleMoves: Point[];
+  level?: number; // strength of a computer player
+  hints?: Hint[]; // scored possible moves, set for the player to move
+}
+
+export type HintLevel = "none" | "moves" | "scored";
+
+export interface Hint {
+  point: Point;
+  score: number; // engine score, higher is better for the player to move
+  best: boolean;
 }
 
 export interface GameStateMessage {
@@ -114,10 +166,51 @@ export interface GameStateMessage {
   message: {
     p1: GameStatePlayer;
     p2: GameStatePlayer;
+    players: GameStatePlayer[]; // every player in token order
     round: number;
     turn: number;
     currentPlayer: string; // player id
-    board: number[][];
+    size: number; // the board has size rows and columns
+    board: number[][]; // 0 empty, 1 to 4 a token, -1 blocked
+    hash: string; // Zobrist hash of the position, hex
+    opening: string; // name of the opening, empty if none
+    position: string; // position string, e.g. "---...--- X"
+    antiReversi: boolean; // the player with fewer discs wins
+    hints: HintLevel;
+    handicap?: Handicap;
+    analysis?: Analysis; // set when the game was started with analysis
+  };
+}
+
+export interface PlayerAnalysis {
+  token: number;
+  mobility: number; // legal moves
+  potentialMobility: number; // empty cells next to an opponent disc
+  frontier: number; // discs next to an empty cell
+  stable: Point[] | null; // discs that can no longer be flipped
+  corners: number;
+  edges: number; // edge cells owned, corners left out
+}
+
+export interface Analysis {
+  players: PlayerAnalysis[];
+  regions: Point[][] | null; // groups of connected empty cells
+  oddRegions: number;
+}
+
+export interface Handicap {
+  player: string; // player id
+  corners: number; // 1 to 4
+}
+
+export interface GameResultMessage {
+  action: ServerMessageType.GameResult;
+  message: {
+    winner: string; // player id, empty for a draw
+    p1: GameStatePlayer;
+    p2: GameStatePlayer;
+    players: GameStatePlayer[];
+    handicap?: Handicap;
   };
 }
 
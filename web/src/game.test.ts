@@ -1,5 +1,5 @@
 import { GameStateMessage, ServerMessageType } from "./definitions";
-import { isCurrentPlayer, player } from "./game";
+import { emptyCells, evalShares, isCurrentPlayer, player } from "./game";
 
 describe("sum function", () => {
   // beforeEach(() => {
@@ -32,10 +32,18 @@ describe("sum function", () => {
             score: 0,
             possibleMoves: [],
           },
+          players: [],
           round: 0,
           turn: 0,
           currentPlayer: "ID_1",
+          size: 8,
           board: [],
+          hash: "",
+          opening: "",
+          position: "",
+          antiReversi: false,
+        hints: "moves",
+          hints: "moves",
         },
       };
 
@@ -64,14 +72,62 @@ describe("sum function", () => {
           score: 0,
           possibleMoves: [],
         },
+        players: [],
         round: 0,
         turn: 0,
         currentPlayer: "ID_1",
+        size: 8,
         board: [],
+        hash: "",
+        opening: "",
+        position: "",
+        antiReversi: false,
       },
     };
 
     expect(isCurrentPlayer(resp)).toBeFalsy();
   });
 
+  describe("evalShares", () => {
+    test("splits the bar by mobility, corners and stable discs", () => {
+      const shares = evalShares({
+        players: [
+          { token: 1, mobility: 3, potentialMobility: 5, frontier: 4, stable: [{ x: 0, y: 0 }], corners: 1, edges: 2 },
+          { token: 2, mobility: 7, potentialMobility: 9, frontier: 2, stable: null, corners: 0, edges: 0 },
+        ],
+        regions: null,
+        oddRegions: 0,
+      });
+
+      expect(shares).toEqual([0.5, 0.5]);
+    });
+
+    test("counts corners and stable discs against their owner in anti-reversi", () => {
+      const shares = evalShares(
+        {
+          players: [
+            { token: 1, mobility: 3, potentialMobility: 5, frontier: 4, stable: [{ x: 0, y: 0 }], corners: 1, edges: 2 },
+            { token: 2, mobility: 7, potentialMobility: 9, frontier: 2, stable: null, corners: 0, edges: 0 },
+          ],
+          regions: null,
+          oddRegions: 0,
+        },
+        true
+      );
+
+      expect(shares).toEqual([0.25, 0.75]);
+    });
+  });
+
+  test("emptyCells lists the cells without a disc", () => {
+    expect(
+      emptyCells([
+        [0, 1],
+        [-1, 0],
+      ])
+    ).toEqual([
+      { x: 0, y: 0 },
+      { x: 1, y: 1 },
+    ]);
+  });
 });
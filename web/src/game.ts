@@ -1,5 +1,7 @@
 import {
+  Analysis,
   GameErrorMessage,
+  GameResultMessage,
   GameStateMessage,
   JoinRoomRequestMessage,
   JoinRoomResponseMessage,
@@ -7,7 +9,11 @@ import {
   LeaveRoomResponseMessage,
   MakeMoveMessage,
   Message,
+  Bot,
+  BotUpdatedMessage,
+  ChallengeRequestMessage,
   ClientMessageType,
+  Computer,
   Player,
   Point,
   RegisterResponseMessage,
@@ -33,6 +39,12 @@ const createRoomButton = document.getElementById(
 ) as HTMLButtonElement;
 const boardElement = document.getElementById("board") as HTMLDivElement;
 const startButton = document.getElementById("start") as HTMLButtonElement;
+const startComputerButton = document.getElementById(
+  "startComputer"
+) as HTMLButtonElement;
+const computerLevelSelect = document.getElementById(
+  "computerLevel"
+) as HTMLSelectElement;
 const leaveRoomButton = document.getElementById(
   "leaveRoom"
 ) as HTMLButtonElement;
@@ -42,6 +54,7 @@ const serverUrl = "ws://localhost:8080/ws";
 let roomUUID: string | null;
 
 const rooms = new Map<string, Room>();
+const bots = new Map<string, Bot>();
 
 export const player: Player = {
   id: "",
@@ -83,10 +96,50 @@ export function initServerMessageHandlers() {
     handleGameState(msg as GameStateMessage)
   );
   registerHandler(ServerMessageType.GameResult, (msg) =>
-    handleGameResult(msg as Message)
+    handleGameResult(msg as GameResultMessage)
+  );
+  registerHandler(ServerMessageType.BotUpdated, (msg) =>
+    handleBotUpdatedMessage(msg as BotUpdatedMessage)
   );
 }
 
+function handleBotUpdatedMessage(resp: BotUpdatedMessage) {
+  if (resp.message.action === "LEFT") {
+    bots.delete(resp.message.id);
+  } else {
+    bots.set(resp.message.id, { id: resp.message.id, name: resp.message.name });
+  }
+  renderBots();
+}
+
+// renderBots lists the bots, which can be challenged from a room.
+function renderBots() {
+  const botsElement = document.getElementById("bots") as HTMLDivElement;
+  botsElement.replaceChildren();
+  for (const bot of bots.values()) {
+    const button = document.createElement("button");
+    button.textContent = `Challenge ${bot.name}`;
+    button.disabled = !roomUUID;
+    button.onclick = () => handleChallengeClick(bot);
+    botsElement.appendChild(button);
+  }
+}
+
+function handleChallengeClick(bot: Bot) {
+  if (!roomUUID) {
+    console.error("Player isn't in any room");
+    return;
+  }
+  const message: ChallengeRequestMessage = {
+    action: ClientMessageType.Challenge,
+    message: {
+      botID: bot.id,
+      game: { roomUUID: roomUUID },
+    },
+  };
+  sendClientMessage(message);
+}
+
 function appendMessageLogs(msg: string) {
   const messageLogs = document.getElementById(
     "messageLogs"
@@ -207,6 +260,8 @@ function handleRegisterResponse(resp: RegisterResponseMessage) {
       count: room.count,
     }))
     .forEach(handleUpsertRoom);
+  resp.message.bots.forEach((bot) => bots.set(bot.id, bot));
+  renderBots();
 }
 
 function handleJoinRoomResponse(resp: JoinRoomResponseMessage) {
@@ -217,6 +272,7 @@ function handleJoinRoomResponse(resp: JoinRoomResponseMessage) {
   roomUUID = resp.message.roomUUID;
   renderEmptyBoard();
   roomElement.hidden = false;
+  renderBots();
 }
 
 function handleLeaveRoomResponse(resp: LeaveRoomResponseMessage) {
@@ -224,6 +280,7 @@ function handleLeaveRoomResponse(resp: LeaveRoomResponseMessage) {
     roomUUID = null;
     roomElement.hidden = true;
     hubElement.hidden = false;
+    renderBots();
   } else {
     console.error("unrelated message", resp);
   }
@@ -239,11 +296,18 @@ function handleGameState(resp: GameStateMessage) {
   renderGameBoard(resp);
 }
 
-function handleGameResult(resp: Message) {
-  if (!resp.message) {
+function handleGameResult(resp: GameResultMessage) {
+  const { players, handicap } = resp.message;
+  let score = players.map((p) => `${p.name} ${p.score}`).join(" - ");
+  if (handicap) {
+    const receiver = players.find((p) => p.id === handicap.player);
+    score += ` (${receiver?.name} had a handicap of ${handicap.corners} corner${handicap.corners > 1 ? "s" : ""})`;
+  }
+  appendMessageLogs(score);
+  if (!resp.message.winner) {
     // Draw
     appendMessageLogs("Draw game!");
-  } else if (resp.message == player.id) {
+  } else if (resp.message.winner == player.id) {
     appendMessageLogs("You win!");
   } else {
     appendMessageLogs("You lose!");
@@ -272,7 +336,7 @@ export function createRoom() {
   sendClientMessage(message);
 }
 
-export function handleStartGameRequest() {
+export function handleStartGameRequest(computer?: Computer) {
   if (!roomUUID) {
     console.error("Player isn't in any room");
     return;
@@ -281,6 +345,7 @@ export function handleStartGameRequest() {
     action: ClientMessageType.StartGame,
     message: {
       roomUUID: roomUUID,
+      computer: computer,
     },
   };
   sendClientMessage(message);
@@ -310,16 +375,52 @@ function renderGameBoard(resp: GameStateMessage) {
   round.textContent = resp.message.round.toString();
   const turn = document.getElementById("turn") as HTMLLabelElement;
   turn.textContent = resp.message.turn.toString();
+  renderEvalBar(resp.message.analysis, resp.message.antiReversi);
   renderBoard(resp);
 }
 
-function renderEmptyBoard() {
+const tokenColors = ["Black", "White", "Crimson", "Gold"];
+
+// evalShares splits the eval bar between the players of analysis. A player
+// gains from moves, corners and stable discs. In anti-reversi games corners
+// and stable discs count against their owner, so a player gains from those of
+// the others instead.
+export function evalShares(analysis: Analysis, antiReversi = false): number[] {
+  const holdings = analysis.players.map(
+    (p) => 3 * p.corners + (p.stable?.length ?? 0)
+  );
+  const held = holdings.reduce((a, b) => a + b, 0);
+  const strengths = analysis.players.map(
+    (p, i) => 1 + p.mobility + (antiReversi ? held - holdings[i] : holdings[i])
+  );
+  const total = strengths.reduce((a, b) => a + b, 0);
+  return strengths.map((s) => s / total);
+}
+
+function renderEvalBar(analysis?: Analysis, antiReversi = false) {
+  const evalBar = document.getElementById("evalBar") as HTMLDivElement;
+  evalBar.innerHTML = "";
+  evalBar.hidden = !analysis;
+  if (!analysis) {
+    return;
+  }
+  evalShares(analysis, antiReversi).forEach((share, i) => {
+    const segment = document.createElement("div");
+    segment.style.width = `${(share * 100).toFixed(1)}%`;
+    segment.style.backgroundColor = tokenColors[i];
+    const p = analysis.players[i];
+    segment.title = `mobility ${p.mobility}, potential ${p.potentialMobility}, frontier ${p.frontier}, stable ${p.stable?.length ?? 0}, corners ${p.corners}, edges ${p.edges}`;
+    evalBar.appendChild(segment);
+  });
+}
+
+function renderEmptyBoard(size = 8) {
   boardElement.innerHTML = "";
   boardElement.hidden = false;
-  for (let row = 0; row < 8; row++) {
+  for (let row = 0; row < size; row++) {
     const rowDiv = document.createElement("div");
     rowDiv.classList.add("board-row");
-    for (let col = 0; col < 8; col++) {
+    for (let col = 0; col < size; col++) {
       const cell = document.createElement("button");
       cell.classList.add("board-cell");
       cell.style.width = "50px";
@@ -347,15 +448,26 @@ function getBoardCell(i: number, j: number): HTMLButtonElement {
 }
 
 function renderBoard(resp: GameStateMessage) {
-  for (let i = 0; i < 8; i++) {
-    for (let j = 0; j < 8; j++) {
+  const size = resp.message.size;
+  if (boardElement.childElementCount !== size) {
+    renderEmptyBoard(size);
+  }
+  for (let i = 0; i < size; i++) {
+    for (let j = 0; j < size; j++) {
       const token = resp.message.board?.[i]?.[j];
       const cell = getBoardCell(i, j);
       cell.disabled = true;
+      cell.textContent = "";
       if (token == 1) {
         cell.style.backgroundColor = "Black";
       } else if (token == 2) {
         cell.style.backgroundColor = "White";
+      } else if (token == 3) {
+        cell.style.backgroundColor = "Crimson";
+      } else if (token == 4) {
+        cell.style.backgroundColor = "Gold";
+      } else if (token == -1) {
+        cell.style.backgroundColor = "DimGray";
       } else {
         cell.style.backgroundColor = "DarkGreen";
       }
@@ -365,21 +477,46 @@ function renderBoard(resp: GameStateMessage) {
     return;
   }
 
-  const possibleMoves: Point[] =
-    resp.message.p1.id === player.id
-      ? resp.message.p1.possibleMoves
-      : resp.message.p2.possibleMoves;
-  if (!possibleMoves) {
+  const current = resp.message.players.find((p) => p.id === player.id);
+  if (!current) {
     return;
   }
-  possibleMoves
+  // Without hints every empty cell can be clicked, so the board gives
+  // nothing away. The server rejects illegal moves.
+  const moves: Point[] =
+    resp.message.hints === "none"
+      ? emptyCells(resp.message.board)
+      : current.possibleMoves;
+  moves
     .map((move) => getBoardCell(move.y, move.x))
     .forEach((cell) => {
       cell.disabled = false;
-      cell.style.backgroundColor = "RoyalBlue";
+      if (resp.message.hints !== "none") {
+        cell.style.backgroundColor = "RoyalBlue";
+      }
       cell.onclick = () =>
         handleCellClick(Number(cell.dataset.row), Number(cell.dataset.col));
     });
+  current.hints?.forEach((hint) => {
+    const cell = getBoardCell(hint.point.y, hint.point.x);
+    cell.textContent = String(hint.score);
+    if (hint.best) {
+      cell.style.backgroundColor = "MediumSeaGreen";
+    }
+  });
+}
+
+// emptyCells lists the empty cells of board.
+export function emptyCells(board: number[][]): Point[] {
+  const res: Point[] = [];
+  board.forEach((row, y) =>
+    row.forEach((token, x) => {
+      if (token === 0) {
+        res.push({ x, y });
+      }
+    })
+  );
+  return res;
 }
 
 function handleCellClick(row: number, col: number) {
@@ -418,7 +555,9 @@ export function handleLeaveRoomClick() {
 export function initButtonEvents() {
   registerButton.onclick = register;
   createRoomButton.onclick = createRoom;
-  startButton.onclick = handleStartGameRequest;
+  startButton.onclick = () => handleStartGameRequest();
+  startComputerButton.onclick = () =>
+    handleStartGameRequest({ level: Number(computerLevelSelect.value) });
   leaveRoomButton.onclick = handleLeaveRoomClick;
 }
 
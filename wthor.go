@@ -0,0 +1,192 @@
+package reversi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"sort"
+)
+
+const (
+	wthorHeaderSize = 16
+	wthorGameSize   = 68
+	wthorMoves      = 60
+)
+
+// WthorGame is one game of a WTHOR database. Players and tournaments are the
+// record numbers of the .JOU and .TRN files that come with the database.
+type WthorGame struct {
+	Tournament int
+	Black      int
+	White      int
+	Year       int
+	// BlackDiscs is the final disc count of black, with the empty cells
+	// given to the winner.
+	BlackDiscs int
+	// TheoreticalScore is black's disc count under perfect play from the
+	// depth given in the file header.
+	TheoreticalScore int
+	// Moves are the moves of the game for this board, black being the player
+	// who moves first. Passes are left out.
+	Moves []Point
+}
+
+// ReadWthor reads the games of a WTHOR .wtb file. Only 8x8 databases are
+// supported and every game is checked to be legal.
+func ReadWthor(r io.Reader) ([]WthorGame, error) {
+	br := bufio.NewReader(r)
+	var header [wthorHeaderSize]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return nil, fmt.Errorf("reading WTHOR header: %w", err)
+	}
+	n := int(binary.LittleEndian.Uint32(header[4:8]))
+	year := int(binary.LittleEndian.Uint16(header[10:12]))
+	if size := header[12]; size != 0 && size != Width {
+		return nil, fmt.Errorf("unsupported WTHOR board size %d", size)
+	}
+
+	games := make([]WthorGame, 0, n)
+	var rec [wthorGameSize]byte
+	for i := 0; i < n; i++ {
+		if _, err := io.ReadFull(br, rec[:]); err != nil {
+			return nil, fmt.Errorf("game %d: %w", i+1, err)
+		}
+		g := WthorGame{
+			Tournament:       int(binary.LittleEndian.Uint16(rec[0:2])),
+			Black:            int(binary.LittleEndian.Uint16(rec[2:4])),
+			White:            int(binary.LittleEndian.Uint16(rec[4:6])),
+			Year:             year,
+			BlackDiscs:       int(rec[6]),
+			TheoreticalScore: int(rec[7]),
+		}
+		for _, b := range rec[wthorGameSize-wthorMoves:] {
+			if b == 0 {
+				break
+			}
+			// Moves are stored as 10*row+column, both counted from 1, on
+			// the standard board.
+			x, y := int(b%10)-1, int(b/10)-1
+			if x < 0 || x >= Width || y < 0 || y >= Height {
+				return nil, fmt.Errorf("game %d: invalid move %d", i+1, b)
+			}
+			g.Moves = append(g.Moves, mirror(Point{x, y}))
+		}
+		if _, err := replayMoves(g.Moves); err != nil {
+			return nil, fmt.Errorf("game %d: %w", i+1, err)
+		}
+		games = append(games, g)
+	}
+	return games, nil
+}
+
+// LoadWthorFile reads the games of the named WTHOR file.
+func LoadWthorFile(name string) ([]WthorGame, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadWthor(f)
+}
+
+// MoveStats tells how a move was played in the games of a GameDatabase.
+// Wins and Draws are counted for the player making the move.
+type MoveStats struct {
+	Move  Point
+	Games int
+	Wins  int
+	Draws int
+}
+
+// WinRate returns the share of games won by the player making the move,
+// counting draws as half a win.
+func (s MoveStats) WinRate() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	return (float64(s.Wins) + float64(s.Draws)/2) / float64(s.Games)
+}
+
+// GameDatabase counts the moves played in a collection of games. Positions
+// are stored in canonical form, so games transposing into each other or
+// played in another orientation share their counts.
+type GameDatabase struct {
+	games int
+	// stats holds, for each canonical position reached by a move, the games
+	// that went through it. Move is unused.
+	stats map[Position]*MoveStats
+}
+
+// NewGameDatabase returns an empty database.
+func NewGameDatabase() *GameDatabase {
+	return &GameDatabase{stats: make(map[Position]*MoveStats)}
+}
+
+// Add counts the moves of g. It fails without counting anything if g holds
+// an illegal move.
+func (d *GameDatabase) Add(g WthorGame) error {
+	if _, err := replayMoves(g.Moves); err != nil {
+		return err
+	}
+	// result is the outcome for the side to move in p.
+	p, result := startPosition(), sign(g.BlackDiscs-Width*Height/2)
+	for _, m := range orientMoves(g.Moves) {
+		if p.Moves() == 0 {
+			p, result = p.Pass(), -result
+		}
+		p = p.Play(m)
+
+		c := p.Canonical()
+		s := d.stats[c]
+		if s == nil {
+			s = &MoveStats{}
+			d.stats[c] = s
+		}
+		s.Games++
+		switch result {
+		case 1:
+			s.Wins++
+		case 0:
+			s.Draws++
+		}
+		result = -result
+	}
+	d.games++
+	return nil
+}
+
+// Len returns the number of games in the database.
+func (d *GameDatabase) Len() int {
+	return d.games
+}
+
+// Stats returns how often each legal move of the player to move in g was
+// played in the database and how it scored, most played first. Moves never
+// played are left out, as are all moves on boards other than the standard
+// one.
+func (d *GameDatabase) Stats(g *GameBoard) []MoveStats {
+	if !g.standard() {
+		return nil
+	}
+	return d.PositionStats(g.Position(g.CurrentPlayer().Token()))
+}
+
+// PositionStats is Stats for the side to move in p.
+func (d *GameDatabase) PositionStats(p Position) []MoveStats {
+	var res []MoveStats
+	for moves := p.Moves(); moves != 0; moves &= moves - 1 {
+		sq := bits.TrailingZeros64(moves)
+		s, ok := d.stats[p.play(sq).Canonical()]
+		if !ok {
+			continue
+		}
+		res = append(res, MoveStats{Move: squarePoint(sq), Games: s.Games, Wins: s.Wins, Draws: s.Draws})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Games > res[j].Games
+	})
+	return res
+}
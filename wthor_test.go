@@ -0,0 +1,144 @@
+package reversi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// wthorFile encodes games written as standard transcripts into a WTHOR
+// database of the given year.
+func wthorFile(t *testing.T, year int, games map[string]int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	header := make([]byte, wthorHeaderSize)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(games)))
+	binary.LittleEndian.PutUint16(header[10:12], uint16(year))
+	header[12] = Width
+	buf.Write(header)
+	for moves, blackDiscs := range games {
+		rec := make([]byte, wthorGameSize)
+		binary.LittleEndian.PutUint16(rec[0:2], 7)
+		binary.LittleEndian.PutUint16(rec[2:4], 1)
+		binary.LittleEndian.PutUint16(rec[4:6], 2)
+		rec[6] = byte(blackDiscs)
+		points, err := ParseMoves(moves)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, p := range points {
+			rec[wthorGameSize-wthorMoves+i] = byte(10*(p.Y+1) + p.X + 1)
+		}
+		buf.Write(rec)
+	}
+	return buf.Bytes()
+}
+
+func TestReadWthor(t *testing.T) {
+	data := wthorFile(t, 2001, map[string]int{"f5d6c3d3c4": 40})
+	games, err := ReadWthor(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 1 {
+		t.Fatalf("len(ReadWthor()), want: 1, got %v", len(games))
+	}
+	g := games[0]
+	if g.Year != 2001 || g.Tournament != 7 || g.Black != 1 || g.White != 2 || g.BlackDiscs != 40 {
+		t.Errorf("ReadWthor(), got %+v", g)
+	}
+	want, _ := ParseMoves("c5e6f3e3f4")
+	if len(g.Moves) != len(want) {
+		t.Fatalf("Moves, want: %v, got %v", want, g.Moves)
+	}
+	for i := range want {
+		if g.Moves[i] != want[i] {
+			t.Errorf("Moves, want: %v, got %v", want, g.Moves)
+			break
+		}
+	}
+
+	tests := map[string][]byte{
+		"short header": data[:10],
+		"short game":   data[:wthorHeaderSize+20],
+		"illegal game": wthorFile(t, 2001, map[string]int{"f5a1": 32}),
+	}
+	for name, data := range tests {
+		if _, err := ReadWthor(bytes.NewReader(data)); err == nil {
+			t.Errorf("%v: ReadWthor(), want error, got nil", name)
+		}
+	}
+}
+
+func TestGameDatabase(t *testing.T) {
+	data := wthorFile(t, 2001, map[string]int{
+		"f5d6c3": 40, // black wins
+		"f5d6c5": 20, // white wins
+		"f5f6e6": 32, // draw
+		"f5f4e3": 50, // black wins
+	})
+	games, err := ReadWthor(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewGameDatabase()
+	for _, g := range games {
+		if err := d.Add(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d.Len() != 4 {
+		t.Errorf("Len(), want: 4, got %v", d.Len())
+	}
+
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	stats := d.Stats(g)
+	// The four opening moves are symmetric, so they share the counts.
+	if len(stats) != 4 || stats[0].Games != 4 || stats[0].Wins != 2 || stats[0].Draws != 1 {
+		t.Fatalf("Stats() at the start, got %+v", stats)
+	}
+	if got, want := stats[0].WinRate(), 0.625; got != want {
+		t.Errorf("WinRate(), want: %v, got %v", want, got)
+	}
+
+	g.Mark(Point{2, 4}, *g.P1()) // f5 on the standard board
+	want := map[Point]MoveStats{
+		{4, 5}: {Games: 2, Wins: 1},  // d6
+		{2, 5}: {Games: 1, Draws: 1}, // f6
+		{2, 3}: {Games: 1},           // f4
+	}
+	stats = d.Stats(g)
+	if len(stats) != len(want) || stats[0].Move != (Point{4, 5}) {
+		t.Fatalf("Stats() after f5, got %+v", stats)
+	}
+	for _, s := range stats {
+		w := want[s.Move]
+		w.Move = s.Move
+		if s != w {
+			t.Errorf("Stats() of %v, want: %+v, got %+v", s.Move, w, s)
+		}
+	}
+
+	// Transcripts are in the standard orientation, and so are the
+	// continuations once named.
+	g, err = ParseTranscript("f5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Notation
+	for _, s := range d.Stats(g) {
+		n, err := g.StandardNotation(s.Move)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, n)
+	}
+	// f6 and f4 were played as often, so only d6 has to come first.
+	if len(got) == 3 && got[1] > got[2] {
+		got[1], got[2] = got[2], got[1]
+	}
+	if want := []Notation{"d6", "f4", "f6"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("continuations of f5, want: %v, got %v", want, got)
+	}
+}
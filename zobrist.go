@@ -0,0 +1,92 @@
+package reversi
+
+// zobristSeed fixes the keys so hashes stay the same across runs and can be
+// stored next to games and positions.
+const zobristSeed = 0x5eed0f0e11051a7e
+
+var (
+	// zobristDiscs holds a key per token and cell.
+	zobristDiscs [2][Width * Height]uint64
+	// zobristSide is mixed in when token 2 is to move.
+	zobristSide uint64
+	// zobristTurn holds the key mixed in for each token to move, none for
+	// token 1 and zobristSide for token 2.
+	zobristTurn [MaxPlayers]uint64
+	// zobristGrid holds a key per token and cell of the other board sizes
+	// and of games of more than two players, indexed y*size+x.
+	zobristGrid [MaxPlayers][MaxSize * MaxSize]uint64
+	// zobristBytes folds the keys of 8 cells at a time, so a Position can be
+	// hashed with 16 lookups.
+	zobristBytes [2][Width * Height / 8][256]uint64
+)
+
+func init() {
+	state := uint64(zobristSeed)
+	for t := range zobristDiscs {
+		for sq := range zobristDiscs[t] {
+			zobristDiscs[t][sq] = splitmix64(&state)
+		}
+	}
+	zobristSide = splitmix64(&state)
+	for t := range zobristGrid {
+		for sq := range zobristGrid[t] {
+			zobristGrid[t][sq] = splitmix64(&state)
+		}
+	}
+	zobristTurn[1] = zobristSide
+	for t := 2; t < MaxPlayers; t++ {
+		zobristTurn[t] = splitmix64(&state)
+	}
+
+	for t := range zobristBytes {
+		for i := range zobristBytes[t] {
+			for b := 0; b < 256; b++ {
+				var h uint64
+				for bit := 0; bit < 8; bit++ {
+					if b&(1<<bit) != 0 {
+						h ^= zobristDiscs[t][i*8+bit]
+					}
+				}
+				zobristBytes[t][i][b] = h
+			}
+		}
+	}
+}
+
+func splitmix64(state *uint64) uint64 {
+	*state += 0x9e3779b97f4a7c15
+	z := *state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// zobristMask returns the combined key of the cells in mask for token.
+func zobristMask(token int, mask uint64) uint64 {
+	var h uint64
+	for i := 0; mask != 0; i, mask = i+1, mask>>8 {
+		h ^= zobristBytes[token-1][i][mask&0xff]
+	}
+	return h
+}
+
+// cellKey returns the key of a disc of token on p.
+func (g GameBoard) cellKey(token int, p Point) uint64 {
+	if g.standard() {
+		return zobristDiscs[token-1][p.Y*Width+p.X]
+	}
+	return zobristGrid[token-1][p.Y*g.cfg.size+p.X]
+}
+
+// Hash returns the Zobrist hash of the position. Own discs are keyed as
+// token 1 and opponent discs as token 2, so the hash equals GameBoard.Hash
+// when token 1 is to move. The side to move is implied by Own and Opp.
+func (p Position) Hash() uint64 {
+	return zobristMask(1, p.Own) ^ zobristMask(2, p.Opp)
+}
+
+// Hash returns the Zobrist hash of the discs and the side to move. Equal
+// positions have equal hashes in every game and every run.
+func (g GameBoard) Hash() uint64 {
+	return g.hash
+}
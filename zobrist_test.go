@@ -0,0 +1,83 @@
+package reversi
+
+import "testing"
+
+func TestHashIncremental(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		playRandomGame(seed, func(g *GameBoard) {
+			want := zobristMask(1, g.bb.own(1)) ^ zobristMask(2, g.bb.own(2))
+			if g.toMove == 2 {
+				want ^= zobristSide
+			}
+			if g.hash != want {
+				t.Fatalf("seed %v turn %v: hash, want: %x, got %x", seed, g.turn, want, g.hash)
+			}
+			if g.CurrentPlayer().token == 1 {
+				if got, want := g.Position(1).Hash(), g.Hash(); got != want {
+					t.Fatalf("seed %v turn %v: Position.Hash(), want: %x, got %x", seed, g.turn, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestHashSideToMove(t *testing.T) {
+	g := NewGameBoard(*NewPlayer(1), *NewPlayer(2))
+	h := g.Hash()
+	if NewGameBoard(*NewPlayer(1), *NewPlayer(2), WithP1First(false)).Hash() == h {
+		t.Errorf("Hash() is the same for both sides to move")
+	}
+	if got, want := NewGameBoard(*NewPlayer(1), *NewPlayer(2)).Hash(), h; got != want {
+		t.Errorf("Hash() of the start position, want: %x, got %x", want, got)
+	}
+}
+
+func TestTranspositionTable(t *testing.T) {
+	tt := NewTranspositionTable(3)
+	if got, want := len(tt.slots), 4; got != want {
+		t.Fatalf("len(slots), want: %v, got %v", want, got)
+	}
+
+	entry := TTEntry{Depth: 5, Score: -123456, Bound: LowerBound, Move: 63}
+	tt.Store(1, entry)
+	if got, ok := tt.Probe(1); !ok || got != entry {
+		t.Errorf("Probe(1), want: %v, got %v", entry, got)
+	}
+	if _, ok := tt.Probe(5); ok {
+		t.Errorf("Probe(5) of another key in the same slot, want miss")
+	}
+
+	// A shallower search of another position does not replace a deeper one.
+	tt.Store(5, TTEntry{Depth: 2, Score: 1, Bound: ExactBound, Move: -1})
+	if _, ok := tt.Probe(1); !ok {
+		t.Errorf("Probe(1) after a shallower store, want hit")
+	}
+
+	// After a new search it does.
+	tt.NewSearch()
+	tt.Store(5, TTEntry{Depth: 2, Score: 1, Bound: ExactBound, Move: -1})
+	if got, ok := tt.Probe(5); !ok || got.Move != -1 || got.Score != 1 {
+		t.Errorf("Probe(5) after a new search, want hit, got %v", got)
+	}
+
+	tt.Clear()
+	if _, ok := tt.Probe(5); ok {
+		t.Errorf("Probe(5) after Clear, want miss")
+	}
+}
+
+func TestSolveSharedTable(t *testing.T) {
+	tt := NewTranspositionTable(1 << 16)
+	s := NewSearcher(WithDepth(4), WithTranspositionTable(tt))
+	for seed := int64(0); seed < 5; seed++ {
+		p := randomPosition(seed, 10)
+		s.Search(p)
+		res, err := Solve(p, Exact, WithSolverTable(tt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := minimax(p); res.Score != want {
+			t.Errorf("seed %v: Solve().Score, want: %v, got %v", seed, want, res.Score)
+		}
+	}
+}